@@ -0,0 +1,185 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type Role string
+
+const (
+	Owners  Role = "owners"
+	Editors Role = "editors"
+	Viewers Role = "viewers"
+	// Members can see an organization but none of its workspaces or documents unless shared with them
+	Members Role = "members"
+	// NoAccess removes a user in an AccessDelta, or stops roles being inherited when used as the max inherited role
+	NoAccess Role = ""
+)
+
+// Access lists the users with access to an organization, workspace or document
+type Access struct {
+	// Only returned for workspaces and documents
+	MaxInheritedRole Role         `json:"maxInheritedRole,omitempty"`
+	Users            []AccessUser `json:"users"`
+}
+
+type AccessUser struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Ref     string `json:"ref,omitempty"`
+	Picture string `json:"picture,omitempty"`
+	// Role granted directly
+	Access Role `json:"access"`
+	// Role inherited from the parent organization or workspace
+	ParentAccess Role `json:"parentAccess,omitempty"`
+	IsMember     bool `json:"isMember,omitempty"`
+}
+
+// AccessDelta describes changes to the access of a resource
+type AccessDelta struct {
+	// Role to set for each email. NoAccess removes the user
+	Users map[string]Role
+	// Limits roles inherited from the parent. Left unchanged when nil. Not supported for organizations
+	MaxInheritedRole *Role
+}
+
+func (d AccessDelta) MarshalJSON() ([]byte, error) {
+	delta := map[string]interface{}{}
+
+	if d.Users != nil {
+		users := make(map[string]*Role, len(d.Users))
+		for k, v := range d.Users {
+			users[k] = nullRole(v)
+		}
+		delta["users"] = users
+	}
+
+	if d.MaxInheritedRole != nil {
+		delta["maxInheritedRole"] = nullRole(*d.MaxInheritedRole)
+	}
+
+	return json.Marshal(map[string]interface{}{"delta": delta})
+}
+
+// nullRole encodes NoAccess as null, which Grist uses to remove a role
+func nullRole(r Role) *Role {
+	if r == NoAccess {
+		return nil
+	}
+
+	return &r
+}
+
+// GetOrgAccess lists the users with access to an organization
+func (c *Client) GetOrgAccess(id string) (Access, error) {
+	return c.GetOrgAccessContext(context.Background(), id)
+}
+
+// GetOrgAccessContext lists the users with access to an organization using the provided context
+func (c *Client) GetOrgAccessContext(ctx context.Context, id string) (Access, error) {
+	return c.getAccess(ctx, fmt.Sprintf("/api/orgs/%s/access", id))
+}
+
+// UpdateOrgAccess changes the access of users to an organization
+func (c *Client) UpdateOrgAccess(id string, delta AccessDelta) error {
+	return c.UpdateOrgAccessContext(context.Background(), id, delta)
+}
+
+// UpdateOrgAccessContext changes the access of users to an organization using the provided context
+func (c *Client) UpdateOrgAccessContext(ctx context.Context, id string, delta AccessDelta) error {
+	return c.updateAccess(ctx, fmt.Sprintf("/api/orgs/%s/access", id), delta)
+}
+
+// GetWorkspaceAccess lists the users with access to a workspace
+func (c *Client) GetWorkspaceAccess(id WorkspaceID) (Access, error) {
+	return c.GetWorkspaceAccessContext(context.Background(), id)
+}
+
+// GetWorkspaceAccessContext lists the users with access to a workspace using the provided context
+func (c *Client) GetWorkspaceAccessContext(ctx context.Context, id WorkspaceID) (Access, error) {
+	return c.getAccess(ctx, fmt.Sprintf("/api/workspaces/%d/access", id))
+}
+
+// UpdateWorkspaceAccess changes the access of users to a workspace
+func (c *Client) UpdateWorkspaceAccess(id WorkspaceID, delta AccessDelta) error {
+	return c.UpdateWorkspaceAccessContext(context.Background(), id, delta)
+}
+
+// UpdateWorkspaceAccessContext changes the access of users to a workspace using the provided context
+func (c *Client) UpdateWorkspaceAccessContext(ctx context.Context, id WorkspaceID, delta AccessDelta) error {
+	return c.updateAccess(ctx, fmt.Sprintf("/api/workspaces/%d/access", id), delta)
+}
+
+// GetDocumentAccess lists the users with access to a document
+func (c *Client) GetDocumentAccess(id DocumentID) (Access, error) {
+	return c.GetDocumentAccessContext(context.Background(), id)
+}
+
+// GetDocumentAccessContext lists the users with access to a document using the provided context
+func (c *Client) GetDocumentAccessContext(ctx context.Context, id DocumentID) (Access, error) {
+	return c.getAccess(ctx, fmt.Sprintf("/api/docs/%s/access", id))
+}
+
+// UpdateDocumentAccess changes the access of users to a document
+func (c *Client) UpdateDocumentAccess(id DocumentID, delta AccessDelta) error {
+	return c.UpdateDocumentAccessContext(context.Background(), id, delta)
+}
+
+// UpdateDocumentAccessContext changes the access of users to a document using the provided context
+func (c *Client) UpdateDocumentAccessContext(ctx context.Context, id DocumentID, delta AccessDelta) error {
+	return c.updateAccess(ctx, fmt.Sprintf("/api/docs/%s/access", id), delta)
+}
+
+func (c *Client) getAccess(ctx context.Context, path string) (Access, error) {
+	var a Access
+
+	request := GristRequest{
+		Path:   path,
+		Method: http.MethodGet,
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return a, err
+	}
+
+	err = json.Unmarshal(resp, &a)
+
+	return a, err
+}
+
+func (c *Client) updateAccess(ctx context.Context, path string, delta AccessDelta) error {
+	data, err := json.Marshal(delta)
+	if err != nil {
+		return err
+	}
+
+	request := GristRequest{
+		Path:   path,
+		Method: http.MethodPatch,
+		Data:   bytes.NewReader(data),
+	}
+
+	_, err = c.httpRequest(ctx, request)
+
+	return err
+}
@@ -0,0 +1,197 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// UserAction is a single Grist user action such as ["AddRecord", "Table", null, {...}].
+// The constructors below cover the common actions; any other action can be
+// built directly from its name and arguments.
+type UserAction []interface{}
+
+// ColumnValues maps column IDs to the values of each row in a bulk action
+type ColumnValues map[string][]interface{}
+
+// ApplyResult is the outcome of applying user actions
+type ApplyResult struct {
+	ActionNum  int    `json:"actionNum"`
+	ActionHash string `json:"actionHash"`
+	// Value returned by each action, such as the row ID from AddRecord
+	RetValues      []json.RawMessage `json:"retValues"`
+	IsModification bool              `json:"isModification"`
+}
+
+// AddRecordAction adds a row with the given fields
+func AddRecordAction(table TableID, fields interface{}) UserAction {
+	return UserAction{"AddRecord", table, nil, fields}
+}
+
+// BulkAddRecordAction adds rows with the given column values. Every column must have a value for each row.
+func BulkAddRecordAction(table TableID, values ColumnValues) (UserAction, error) {
+	rows := -1
+	for k, v := range values {
+		if rows == -1 {
+			rows = len(v)
+			continue
+		}
+
+		if len(v) != rows {
+			return nil, fmt.Errorf("column %s has %d values but other columns have %d", k, len(v), rows)
+		}
+	}
+
+	if rows < 1 {
+		return nil, errors.New("column values required to add records")
+	}
+
+	return UserAction{"BulkAddRecord", table, make([]interface{}, rows), values}, nil
+}
+
+// UpdateRecordAction sets the fields of an existing row
+func UpdateRecordAction(table TableID, id int, fields interface{}) UserAction {
+	return UserAction{"UpdateRecord", table, id, fields}
+}
+
+// BulkUpdateRecordAction sets the column values of existing rows. Every column must have a value for each row ID.
+func BulkUpdateRecordAction(table TableID, ids []int, values ColumnValues) (UserAction, error) {
+	if len(ids) < 1 {
+		return nil, errors.New("row IDs required to update records")
+	}
+
+	for k, v := range values {
+		if len(v) != len(ids) {
+			return nil, fmt.Errorf("column %s has %d values but there are %d row IDs", k, len(v), len(ids))
+		}
+	}
+
+	return UserAction{"BulkUpdateRecord", table, ids, values}, nil
+}
+
+// RemoveRecordAction removes a row
+func RemoveRecordAction(table TableID, id int) UserAction {
+	return UserAction{"RemoveRecord", table, id}
+}
+
+// BulkRemoveRecordAction removes rows
+func BulkRemoveRecordAction(table TableID, ids []int) UserAction {
+	return UserAction{"BulkRemoveRecord", table, ids}
+}
+
+// AddTableAction adds a table with the given columns
+func AddTableAction(table TableID, columns ...Column) (UserAction, error) {
+	cols := make([]map[string]interface{}, 0, len(columns))
+	for _, v := range columns {
+		col, err := columnSpec(v.Fields)
+		if err != nil {
+			return nil, err
+		}
+
+		col["id"] = v.ID
+		cols = append(cols, col)
+	}
+
+	return UserAction{"AddTable", table, cols}, nil
+}
+
+// RemoveTableAction removes a table and its data
+func RemoveTableAction(table TableID) UserAction {
+	return UserAction{"RemoveTable", table}
+}
+
+// RenameTableAction changes the ID of a table
+func RenameTableAction(table, newID TableID) UserAction {
+	return UserAction{"RenameTable", table, newID}
+}
+
+// AddColumnAction adds a column to a table
+func AddColumnAction(table TableID, column Column) (UserAction, error) {
+	col, err := columnSpec(column.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return UserAction{"AddColumn", table, column.ID, col}, nil
+}
+
+// ModifyColumnAction changes the given fields of a column, e.g. map[string]interface{}{"type": "Int"}
+func ModifyColumnAction(table TableID, column string, fields interface{}) UserAction {
+	return UserAction{"ModifyColumn", table, column, fields}
+}
+
+// RemoveColumnAction removes a column and its data
+func RemoveColumnAction(table TableID, column string) UserAction {
+	return UserAction{"RemoveColumn", table, column}
+}
+
+// RenameColumnAction changes the ID of a column
+func RenameColumnAction(table TableID, column, newID string) UserAction {
+	return UserAction{"RenameColumn", table, column, newID}
+}
+
+// columnSpec encodes the column fields as the object used by column actions
+func columnSpec(f ColumnField) (map[string]interface{}, error) {
+	data, err := json.Marshal(&f)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// ApplyUserActions applies the actions to the document atomically. Either all actions succeed or none are applied.
+func (c *Client) ApplyUserActions(document DocumentID, actions ...UserAction) (ApplyResult, error) {
+	return c.ApplyUserActionsContext(context.Background(), document, actions...)
+}
+
+// ApplyUserActionsContext applies the actions to the document atomically using the provided context
+func (c *Client) ApplyUserActionsContext(ctx context.Context, document DocumentID, actions ...UserAction) (ApplyResult, error) {
+	var result ApplyResult
+
+	if len(actions) < 1 {
+		return result, errors.New("actions required to apply user actions")
+	}
+
+	data, err := json.Marshal(actions)
+	if err != nil {
+		return result, err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/apply", document),
+		Method: http.MethodPost,
+		Data:   bytes.NewReader(data),
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return result, err
+	}
+
+	err = json.Unmarshal(resp, &result)
+
+	return result, err
+}
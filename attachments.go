@@ -0,0 +1,144 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// AttachmentFile is a file to upload as an attachment
+type AttachmentFile struct {
+	Name string
+	Data io.Reader
+}
+
+// AttachmentMetadata describes an uploaded attachment
+type AttachmentMetadata struct {
+	FileName     string    `json:"fileName"`
+	FileSize     int       `json:"fileSize"`
+	TimeUploaded time.Time `json:"timeUploaded"`
+}
+
+// AttachmentsCell returns the value to store the attachments in an Attachments column
+func AttachmentsCell(ids ...int) List[int] {
+	return List[int](ids)
+}
+
+// UploadAttachments uploads files to the document and returns their attachment IDs
+func (c *Client) UploadAttachments(document DocumentID, files ...AttachmentFile) ([]int, error) {
+	return c.UploadAttachmentsContext(context.Background(), document, files...)
+}
+
+// UploadAttachmentsContext uploads files to the document using the provided context
+func (c *Client) UploadAttachmentsContext(ctx context.Context, document DocumentID, files ...AttachmentFile) ([]int, error) {
+	if len(files) < 1 {
+		return nil, errors.New("files required to upload attachments")
+	}
+
+	for _, v := range files {
+		if v.Data == nil {
+			return nil, fmt.Errorf("data required to upload attachment %q", v.Name)
+		}
+	}
+
+	resp, err := c.upload(ctx, fmt.Sprintf("/api/docs/%s/attachments", document), nil, files...)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	if err := json.Unmarshal(resp, &ids); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// ListAttachments gets the metadata of the attachments in a document. The filter, sort and limit options are
+// supported. The client global filter is not applied.
+func (c *Client) ListAttachments(document DocumentID, opts ...GristRequestOpt) ([]Record[AttachmentMetadata], error) {
+	return c.ListAttachmentsContext(context.Background(), document, opts...)
+}
+
+// ListAttachmentsContext gets the metadata of the attachments in a document using the provided context
+func (c *Client) ListAttachmentsContext(ctx context.Context, document DocumentID, opts ...GristRequestOpt) ([]Record[AttachmentMetadata], error) {
+	var r GristRequest
+
+	for _, opt := range opts {
+		opt(&r)
+	}
+
+	r.Path = fmt.Sprintf("/api/docs/%s/attachments", document)
+	r.Method = http.MethodGet
+	// the global filter is meant for table records and its columns don't exist here
+	r.SkipGlobalFilter = true
+
+	resp, err := c.httpRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRecords[AttachmentMetadata](resp)
+}
+
+// GetAttachmentMetadata gets the metadata of a single attachment
+func (c *Client) GetAttachmentMetadata(document DocumentID, id int) (AttachmentMetadata, error) {
+	return c.GetAttachmentMetadataContext(context.Background(), document, id)
+}
+
+// GetAttachmentMetadataContext gets the metadata of a single attachment using the provided context
+func (c *Client) GetAttachmentMetadataContext(ctx context.Context, document DocumentID, id int) (AttachmentMetadata, error) {
+	var m AttachmentMetadata
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/attachments/%d", document, id),
+		Method: http.MethodGet,
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return m, err
+	}
+
+	err = json.Unmarshal(resp, &m)
+
+	return m, err
+}
+
+// DownloadAttachment returns the contents of an attachment. The caller must close the returned reader.
+func (c *Client) DownloadAttachment(document DocumentID, id int) (io.ReadCloser, error) {
+	return c.DownloadAttachmentContext(context.Background(), document, id)
+}
+
+// DownloadAttachmentContext returns the contents of an attachment using the provided context
+func (c *Client) DownloadAttachmentContext(ctx context.Context, document DocumentID, id int) (io.ReadCloser, error) {
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/attachments/%d/download", document, id),
+		Method: http.MethodGet,
+	}
+
+	resp, err := c.do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
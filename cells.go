@@ -0,0 +1,578 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CellCode is the leading type code Grist uses for non-primitive cell values
+type CellCode string
+
+const (
+	ListCode           CellCode = "L"
+	LookUpCode         CellCode = "l"
+	DictCode           CellCode = "O"
+	DateTimeCode       CellCode = "D"
+	DateCode           CellCode = "d"
+	SkipCode           CellCode = "S"
+	CensoredCode       CellCode = "C"
+	ReferenceCode      CellCode = "R"
+	ReferenceListCode  CellCode = "r"
+	ExceptionCode      CellCode = "E"
+	PendingCode        CellCode = "P"
+	UnmarshallableCode CellCode = "U"
+	VersionsCode       CellCode = "V"
+)
+
+// List is a list cell value, encoded by Grist as ["L", item...]
+type List[T any] []T
+
+// RefList is the value of a RefList column
+type RefList = List[int]
+
+// ChoiceList is the value of a ChoiceList column
+type ChoiceList = List[string]
+
+func (l List[T]) MarshalJSON() ([]byte, error) {
+	out := make([]interface{}, 0, len(l)+1)
+	out = append(out, ListCode)
+	for _, v := range l {
+		out = append(out, v)
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON accepts ["L", ...], ["r", table, [...]] and plain JSON arrays
+func (l *List[T]) UnmarshalJSON(b []byte) error {
+	if isNull(b) {
+		*l = nil
+		return nil
+	}
+
+	code, args, err := decodeTagged(b)
+	if err != nil {
+		return err
+	}
+
+	switch code {
+	case "":
+		// plain array, already flattened
+	case ListCode:
+	case ReferenceListCode:
+		if len(args) != 2 {
+			return fmt.Errorf("invalid reference list value: %s", b)
+		}
+		if err := json.Unmarshal(args[1], &args); err != nil {
+			return err
+		}
+	default:
+		return unexpectedCode(code, b)
+	}
+
+	list := make(List[T], 0, len(args))
+	for _, v := range args {
+		var item T
+		if err := json.Unmarshal(v, &item); err != nil {
+			return err
+		}
+		list = append(list, item)
+	}
+
+	*l = list
+
+	return nil
+}
+
+// Ref is a reference to a row in another table, encoded by Grist as ["R", table, id]
+type Ref struct {
+	Table TableID
+	ID    int
+}
+
+func (r Ref) MarshalJSON() ([]byte, error) {
+	if r.Table == "" {
+		return json.Marshal(r.ID)
+	}
+
+	return json.Marshal([]interface{}{ReferenceCode, r.Table, r.ID})
+}
+
+// UnmarshalJSON accepts ["R", table, id] and the plain row ID returned for Ref columns
+func (r *Ref) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if isNull(b) {
+		*r = Ref{}
+		return nil
+	}
+
+	if len(b) == 0 || b[0] != '[' {
+		return json.Unmarshal(b, &r.ID)
+	}
+
+	code, args, err := decodeTagged(b)
+	if err != nil {
+		return err
+	}
+
+	if code != ReferenceCode {
+		return unexpectedCode(code, b)
+	}
+
+	if len(args) != 2 {
+		return fmt.Errorf("invalid reference value: %s", b)
+	}
+
+	if err := json.Unmarshal(args[0], &r.Table); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(args[1], &r.ID)
+}
+
+// GristDate is the value of a Date column. Grist stores dates as seconds since
+// the epoch at midnight UTC, encoded as a number or ["d", seconds].
+type GristDate struct {
+	time.Time
+}
+
+func (d GristDate) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(d.Unix())
+}
+
+func (d *GristDate) UnmarshalJSON(b []byte) error {
+	secs, _, err := decodeTime(b, DateCode)
+	if err != nil {
+		return err
+	}
+
+	d.Time = secs
+
+	return nil
+}
+
+// GristDateTime is the value of a DateTime column, encoded as a number of
+// seconds since the epoch or ["D", seconds, timezone].
+type GristDateTime struct {
+	time.Time
+	Timezone string
+}
+
+func (d GristDateTime) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(float64(d.UnixNano()) / float64(time.Second))
+}
+
+func (d *GristDateTime) UnmarshalJSON(b []byte) error {
+	t, tz, err := decodeTime(b, DateTimeCode)
+	if err != nil {
+		return err
+	}
+
+	d.Time = t
+	d.Timezone = tz
+
+	if loc, err := time.LoadLocation(tz); err == nil && tz != "" {
+		d.Time = t.In(loc)
+	}
+
+	return nil
+}
+
+// CellError is a formula error stored in a cell, encoded as ["E", name, message, details]
+type CellError struct {
+	Name    string
+	Message string
+	Details json.RawMessage
+}
+
+func (e *CellError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("grist cell error: %s", e.Name)
+	}
+
+	return fmt.Sprintf("grist cell error: %s: %s", e.Name, e.Message)
+}
+
+func (e CellError) MarshalJSON() ([]byte, error) {
+	out := []interface{}{ExceptionCode, e.Name}
+	if e.Message != "" || e.Details != nil {
+		out = append(out, e.Message)
+	}
+	if e.Details != nil {
+		out = append(out, e.Details)
+	}
+
+	return json.Marshal(out)
+}
+
+func (e *CellError) UnmarshalJSON(b []byte) error {
+	code, args, err := decodeTagged(b)
+	if err != nil {
+		return err
+	}
+
+	if code != ExceptionCode || len(args) < 1 {
+		return fmt.Errorf("invalid error value: %s", b)
+	}
+
+	*e = CellError{}
+
+	if err := json.Unmarshal(args[0], &e.Name); err != nil {
+		return err
+	}
+
+	if len(args) > 1 {
+		if err := json.Unmarshal(args[1], &e.Message); err != nil {
+			return err
+		}
+	}
+
+	if len(args) > 2 {
+		e.Details = args[2]
+	}
+
+	return nil
+}
+
+// DecodeCell decodes a raw cell value into the matching Go type. Lists become
+// []interface{}, references Ref, dates GristDate and GristDateTime, and errors
+// *CellError. Primitive values are decoded as by encoding/json.
+func DecodeCell(b json.RawMessage) (interface{}, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || b[0] != '[' {
+		var v interface{}
+		err := json.Unmarshal(b, &v)
+		return v, err
+	}
+
+	code, args, err := decodeTagged(b)
+	if err != nil {
+		return nil, err
+	}
+
+	switch code {
+	case "", ListCode:
+		var l List[json.RawMessage]
+		if err := l.UnmarshalJSON(b); err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, 0, len(l))
+		for _, v := range l {
+			item, err := DecodeCell(v)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, item)
+		}
+		return out, nil
+	case ReferenceCode:
+		var r Ref
+		err := r.UnmarshalJSON(b)
+		return r, err
+	case ReferenceListCode:
+		var r RefList
+		err := r.UnmarshalJSON(b)
+		return r, err
+	case DateCode:
+		var d GristDate
+		err := d.UnmarshalJSON(b)
+		return d, err
+	case DateTimeCode:
+		var d GristDateTime
+		err := d.UnmarshalJSON(b)
+		return d, err
+	case ExceptionCode:
+		var e CellError
+		if err := e.UnmarshalJSON(b); err != nil {
+			return nil, err
+		}
+		return &e, nil
+	case DictCode:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("invalid dict value: %s", b)
+		}
+		var m map[string]interface{}
+		err := json.Unmarshal(args[0], &m)
+		return m, err
+	default:
+		return nil, fmt.Errorf("unsupported cell value type %q", code)
+	}
+}
+
+var cellCodes = map[CellCode]bool{
+	ListCode:           true,
+	LookUpCode:         true,
+	DictCode:           true,
+	DateTimeCode:       true,
+	DateCode:           true,
+	SkipCode:           true,
+	CensoredCode:       true,
+	ReferenceCode:      true,
+	ReferenceListCode:  true,
+	ExceptionCode:      true,
+	PendingCode:        true,
+	UnmarshallableCode: true,
+	VersionsCode:       true,
+}
+
+// decodeTagged splits an encoded cell value into its type code and arguments.
+// Arrays without a leading type code are returned with an empty code.
+func decodeTagged(b []byte) (CellCode, []json.RawMessage, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(b, &items); err != nil {
+		return "", nil, err
+	}
+
+	if len(items) == 0 {
+		return "", items, nil
+	}
+
+	var code CellCode
+	if err := json.Unmarshal(items[0], &code); err != nil || !cellCodes[code] {
+		return "", items, nil
+	}
+
+	return code, items[1:], nil
+}
+
+func decodeTime(b []byte, expected CellCode) (time.Time, string, error) {
+	b = bytes.TrimSpace(b)
+	if isNull(b) {
+		return time.Time{}, "", nil
+	}
+
+	var tz string
+	raw := json.RawMessage(b)
+
+	if len(b) > 0 && b[0] == '[' {
+		code, args, err := decodeTagged(b)
+		if err != nil {
+			return time.Time{}, "", err
+		}
+
+		if code != expected || len(args) < 1 {
+			return time.Time{}, "", unexpectedCode(code, b)
+		}
+
+		raw = args[0]
+
+		if len(args) > 1 {
+			if err := json.Unmarshal(args[1], &tz); err != nil {
+				return time.Time{}, "", err
+			}
+		}
+	}
+
+	var secs float64
+	if err := json.Unmarshal(raw, &secs); err != nil {
+		return time.Time{}, "", err
+	}
+
+	// split the seconds so dates outside the int64 nanosecond range don't overflow
+	sec, frac := math.Modf(secs)
+
+	return time.Unix(int64(sec), int64(math.Round(frac*float64(time.Second)))).UTC(), tz, nil
+}
+
+func unexpectedCode(code CellCode, b []byte) error {
+	if code == ExceptionCode {
+		var e CellError
+		if err := e.UnmarshalJSON(b); err != nil {
+			return err
+		}
+		return &e
+	}
+
+	return fmt.Errorf("unexpected cell value type %q: %s", code, b)
+}
+
+// flattenFields rewrites ["L", ...] values as plain JSON arrays so they can be
+// decoded into ordinary Go slices. Fields of t that decode themselves, such as
+// List, are left untouched.
+func flattenFields(t reflect.Type, fields map[string]json.RawMessage) {
+	custom := customFields(t)
+	for k, v := range fields {
+		if custom[strings.ToLower(k)] {
+			continue
+		}
+		fields[k] = flattenList(v)
+	}
+}
+
+var (
+	customFieldCache sync.Map
+	listFieldCache   sync.Map
+)
+
+// customFields returns the lower cased JSON names of the fields in struct t
+// whose types implement json.Unmarshaler
+func customFields(t reflect.Type) map[string]bool {
+	unmarshaler := reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+	return matchFields(t, &customFieldCache, func(ft reflect.Type) bool {
+		return ft.Implements(unmarshaler) || reflect.PointerTo(ft).Implements(unmarshaler)
+	})
+}
+
+// listFields returns the lower cased JSON names of the fields in struct t
+// that are plain slices or arrays, which are written as Grist lists
+func listFields(t reflect.Type) map[string]bool {
+	marshaler := reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+	return matchFields(t, &listFieldCache, func(ft reflect.Type) bool {
+		if ft.Implements(marshaler) || reflect.PointerTo(ft).Implements(marshaler) {
+			return false
+		}
+
+		// []byte is encoded as a base64 string
+		if ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Uint8 {
+			return false
+		}
+
+		return ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array
+	})
+}
+
+// matchFields returns the lower cased JSON names of the fields in struct t
+// whose types match. Pointer field types are matched by the type they point
+// to, and the results are cached per type.
+func matchFields(t reflect.Type, cache *sync.Map, match func(reflect.Type) bool) map[string]bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if cached, ok := cache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	fields := map[string]bool{}
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+				continue
+			}
+
+			name, _, _ := strings.Cut(tag, ",")
+			if f.Anonymous && name == "" {
+				for k := range matchFields(f.Type, cache, match) {
+					fields[k] = true
+				}
+				continue
+			}
+
+			if name == "" {
+				name = f.Name
+			}
+
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if match(ft) {
+				fields[strings.ToLower(name)] = true
+			}
+		}
+	}
+
+	cache.Store(t, fields)
+
+	return fields
+}
+
+// encodeFields marshals the fields of a record, encoding plain slice fields
+// as Grist lists so fields like []int can be written to RefList columns
+func encodeFields(v interface{}) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return data, nil
+	}
+
+	lists := listFields(t)
+	if len(lists) == 0 {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return data, nil
+	}
+
+	for k, v := range fields {
+		if !lists[strings.ToLower(k)] {
+			continue
+		}
+
+		var values []json.RawMessage
+		if err := json.Unmarshal(v, &values); err != nil || values == nil {
+			continue
+		}
+
+		list, err := json.Marshal(append([]json.RawMessage{json.RawMessage(`"L"`)}, values...))
+		if err != nil {
+			return nil, err
+		}
+
+		fields[k] = list
+	}
+
+	return json.Marshal(fields)
+}
+
+func flattenList(b json.RawMessage) json.RawMessage {
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return b
+	}
+
+	code, args, err := decodeTagged(trimmed)
+	if err != nil || code != ListCode {
+		return b
+	}
+
+	flat, err := json.Marshal(args)
+	if err != nil {
+		return b
+	}
+
+	return flat
+}
+
+func isNull(b []byte) bool {
+	return bytes.Equal(bytes.TrimSpace(b), []byte("null"))
+}
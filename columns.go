@@ -16,10 +16,13 @@ package gorist
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 type FieldType string
@@ -32,6 +35,14 @@ const (
 	BoolField    FieldType = "Bool"
 	DateField    FieldType = "Date"
 	ChoiceField  FieldType = "Choice"
+
+	// Base types of the parameterized types returned by FieldType.Parse
+	DateTimeField FieldType = "DateTime"
+	RefField      FieldType = "Ref"
+	RefListField  FieldType = "RefList"
+
+	ChoiceListField  FieldType = "ChoiceList"
+	AttachmentsField FieldType = "Attachments"
 )
 
 type Recalc int
@@ -50,6 +61,66 @@ func NewRefField(tableID string) FieldType {
 	return FieldType(fmt.Sprintf("Ref:%s", tableID))
 }
 
+func NewRefListField(tableID string) FieldType {
+	return FieldType(fmt.Sprintf("RefList:%s", tableID))
+}
+
+// FieldTypeInfo is the structured form of a FieldType
+type FieldTypeInfo struct {
+	// Type without its parameter, e.g. DateTimeField for DateTime:America/New_York
+	Base FieldType
+	// Timezone of a DateTime column
+	Timezone string
+	// Referenced table of a Ref or RefList column
+	RefTable TableID
+}
+
+// Parse splits a type such as DateTime:America/New_York or Ref:Customers into its parts
+func (f FieldType) Parse() FieldTypeInfo {
+	base, param, _ := strings.Cut(string(f), ":")
+
+	info := FieldTypeInfo{
+		Base: FieldType(base),
+	}
+
+	switch info.Base {
+	case DateTimeField:
+		info.Timezone = param
+	case RefField, RefListField:
+		info.RefTable = TableID(param)
+	}
+
+	return info
+}
+
+// ColumnUpsertOptions are the query options for UpsertColumns
+type ColumnUpsertOptions struct {
+	// Don't add columns missing from the table
+	NoAdd bool
+	// Don't update columns that already exist
+	NoUpdate bool
+	// Remove columns of the table that aren't listed
+	ReplaceAll bool
+}
+
+func (o ColumnUpsertOptions) values() url.Values {
+	q := url.Values{}
+
+	if o.NoAdd {
+		q.Set("noadd", "true")
+	}
+
+	if o.NoUpdate {
+		q.Set("noupdate", "true")
+	}
+
+	if o.ReplaceAll {
+		q.Set("replaceall", "true")
+	}
+
+	return q
+}
+
 type Columns struct {
 	Columns []Column `json:"columns"`
 }
@@ -87,29 +158,118 @@ func (f *ColumnField) MarshalJSON() ([]byte, error) {
 
 }
 
+// UnmarshalJSON decodes the column fields returned by Grist, which encode recalcDeps as ["L", ...]
+func (f *ColumnField) UnmarshalJSON(b []byte) error {
+	type FieldAlias ColumnField
+	t := &struct {
+		*FieldAlias
+		RecalcDeps List[int] `json:"recalcDeps"`
+	}{
+		FieldAlias: (*FieldAlias)(f),
+	}
+
+	if err := json.Unmarshal(b, t); err != nil {
+		return err
+	}
+
+	f.RecalcDeps = t.RecalcDeps
+
+	return nil
+}
+
 func (c *Client) GetColumns(document DocumentID, table Table) (json.RawMessage, error) {
+	return c.GetColumnsContext(context.Background(), document, table)
+}
+
+// GetColumnsContext gets the columns of a table using the provided context
+func (c *Client) GetColumnsContext(ctx context.Context, document DocumentID, table Table) (json.RawMessage, error) {
 	request := GristRequest{
 		Path:   fmt.Sprintf("/api/docs/%s/tables/%s/columns", document, table.ID),
 		Method: http.MethodGet,
 	}
 
-	return c.httpRequest(request)
+	return c.httpRequest(ctx, request)
+}
+
+// GetColumnsTyped gets the columns of a table
+func (c *Client) GetColumnsTyped(document DocumentID, table Table) ([]Column, error) {
+	return c.GetColumnsTypedContext(context.Background(), document, table)
+}
+
+// GetColumnsTypedContext gets the columns of a table using the provided context
+func (c *Client) GetColumnsTypedContext(ctx context.Context, document DocumentID, table Table) ([]Column, error) {
+	resp, err := c.GetColumnsContext(ctx, document, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var cols Columns
+	if err := json.Unmarshal(resp, &cols); err != nil {
+		return nil, err
+	}
+
+	return cols.Columns, nil
 }
 
 func (c *Client) CreateColumns(document DocumentID, table Table, columns ...Column) (json.RawMessage, error) {
-	return c.writeColumns(http.MethodPost, document, table, columns...)
+	return c.writeColumns(context.Background(), http.MethodPost, document, table, nil, columns...)
+}
+
+// CreateColumnsContext adds columns to a table using the provided context
+func (c *Client) CreateColumnsContext(ctx context.Context, document DocumentID, table Table, columns ...Column) (json.RawMessage, error) {
+	return c.writeColumns(ctx, http.MethodPost, document, table, nil, columns...)
 }
 
 func (c *Client) PatchColumns(document DocumentID, table Table, columns ...Column) (json.RawMessage, error) {
-	return c.writeColumns(http.MethodPatch, document, table, columns...)
+	return c.writeColumns(context.Background(), http.MethodPatch, document, table, nil, columns...)
 }
 
-func (c *Client) writeColumns(method string, document DocumentID, table Table, columns ...Column) (json.RawMessage, error) {
+// PatchColumnsContext modifies existing columns of a table using the provided context
+func (c *Client) PatchColumnsContext(ctx context.Context, document DocumentID, table Table, columns ...Column) (json.RawMessage, error) {
+	return c.writeColumns(ctx, http.MethodPatch, document, table, nil, columns...)
+}
+
+// UpsertColumns adds missing columns and updates existing columns so the table
+// matches the given columns. With ReplaceAll set, other columns are removed.
+func (c *Client) UpsertColumns(document DocumentID, table Table, opts ColumnUpsertOptions, columns ...Column) (json.RawMessage, error) {
+	return c.writeColumns(context.Background(), http.MethodPut, document, table, opts.values(), columns...)
+}
+
+// UpsertColumnsContext adds or updates columns of a table using the provided context
+func (c *Client) UpsertColumnsContext(ctx context.Context, document DocumentID, table Table, opts ColumnUpsertOptions, columns ...Column) (json.RawMessage, error) {
+	return c.writeColumns(ctx, http.MethodPut, document, table, opts.values(), columns...)
+}
+
+// DeleteColumn removes a column and its data from a table
+func (c *Client) DeleteColumn(document DocumentID, table Table, column string) (json.RawMessage, error) {
+	return c.DeleteColumnContext(context.Background(), document, table, column)
+}
+
+// DeleteColumnContext removes a column from a table using the provided context
+func (c *Client) DeleteColumnContext(ctx context.Context, document DocumentID, table Table, column string) (json.RawMessage, error) {
+	if column == "" {
+		return nil, errors.New("column ID required to delete column")
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/tables/%s/columns/%s", document, table.ID, column),
+		Method: http.MethodDelete,
+	}
+
+	return c.httpRequest(ctx, request)
+}
+
+func (c *Client) writeColumns(ctx context.Context, method string, document DocumentID, table Table, q url.Values, columns ...Column) (json.RawMessage, error) {
 	cols := Columns{
 		Columns: columns,
 	}
 
-	data, err := json.Marshal(cols)
+	return c.sendColumns(ctx, method, document, table, q, cols)
+}
+
+// sendColumns sends body, which holds the columns in the {"columns": [...]} format, to the columns endpoint of the table
+func (c *Client) sendColumns(ctx context.Context, method string, document DocumentID, table Table, q url.Values, body interface{}) (json.RawMessage, error) {
+	data, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
@@ -117,9 +277,10 @@ func (c *Client) writeColumns(method string, document DocumentID, table Table, c
 	request := GristRequest{
 		Path:   fmt.Sprintf("/api/docs/%s/tables/%s/columns", document, table.ID),
 		Method: method,
+		Query:  q,
 		Data:   bytes.NewReader(data),
 	}
 
-	return c.httpRequest(request)
+	return c.httpRequest(ctx, request)
 
 }
@@ -16,9 +16,14 @@ package gorist
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -29,6 +34,43 @@ type NewDocRequest struct {
 	IsPinned bool   `json:"isPinned"`
 }
 
+// DocumentImport is a file to create a new document from
+type DocumentImport struct {
+	// Name of the new document. Grist names it after the file when empty
+	Name string
+	// Name of the uploaded file. The extension determines how it is imported, e.g. .grist, .csv or .xlsx
+	Filename string
+	Data     io.Reader
+}
+
+// DocumentCopy describes the copy made by CopyDocument
+type DocumentCopy struct {
+	Workspace WorkspaceID `json:"workspaceId"`
+	Name      string      `json:"documentName"`
+	// Copy only the structure of the document, leaving out the data
+	AsTemplate bool `json:"asTemplate,omitempty"`
+}
+
+// Fork identifies a fork of a document
+type Fork struct {
+	ForkID string     `json:"forkId"`
+	DocID  DocumentID `json:"docId"`
+	URLID  string     `json:"urlId"`
+}
+
+// DocumentReplace is the source used to replace the contents of a document.
+// Set either SourceDocID or SnapshotID.
+type DocumentReplace struct {
+	SourceDocID DocumentID `json:"sourceDocId,omitempty"`
+	SnapshotID  string     `json:"snapshotId,omitempty"`
+}
+
+// DocumentUpdate holds changes to a document. Empty fields are left unchanged
+type DocumentUpdate struct {
+	Name     string `json:"name,omitempty"`
+	IsPinned *bool  `json:"isPinned,omitempty"`
+}
+
 type Document struct {
 	Name      string        `json:"name"`
 	CreatedAt time.Time     `json:"createdAt,omitempty"`
@@ -86,7 +128,12 @@ type Aliases struct {
 }
 
 func (c *Client) CreateDocument(workspace WorkspaceID, name string, isPinned bool) (string, error) {
-	id, err := c.createDocument(workspace, name, isPinned)
+	return c.CreateDocumentContext(context.Background(), workspace, name, isPinned)
+}
+
+// CreateDocumentContext creates an empty document in the workspace using the provided context
+func (c *Client) CreateDocumentContext(ctx context.Context, workspace WorkspaceID, name string, isPinned bool) (string, error) {
+	id, err := c.createDocument(ctx, workspace, name, isPinned)
 	if err != nil {
 		return "", err
 	}
@@ -95,7 +142,7 @@ func (c *Client) CreateDocument(workspace WorkspaceID, name string, isPinned boo
 
 }
 
-func (c *Client) createDocument(workspace WorkspaceID, name string, isPinned bool) (json.RawMessage, error) {
+func (c *Client) createDocument(ctx context.Context, workspace WorkspaceID, name string, isPinned bool) (json.RawMessage, error) {
 	doc := NewDocRequest{
 		Name:     name,
 		IsPinned: isPinned,
@@ -112,7 +159,7 @@ func (c *Client) createDocument(workspace WorkspaceID, name string, isPinned boo
 		Data:   bytes.NewReader(data),
 	}
 
-	resp, err := c.httpRequest(request)
+	resp, err := c.httpRequest(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -122,18 +169,252 @@ func (c *Client) createDocument(workspace WorkspaceID, name string, isPinned boo
 }
 
 func (c *Client) GetDocument(id string) (json.RawMessage, error) {
+	return c.GetDocumentContext(context.Background(), id)
+}
+
+// GetDocumentContext gets the details of a document using the provided context
+func (c *Client) GetDocumentContext(ctx context.Context, id string) (json.RawMessage, error) {
 	request := GristRequest{
 		Path:   fmt.Sprintf("/api/docs/%s", id),
 		Method: http.MethodGet,
 	}
-	return c.httpRequest(request)
+	return c.httpRequest(ctx, request)
 }
 
 func (c *Client) DeleteDocument(id string) (json.RawMessage, error) {
+	return c.DeleteDocumentContext(context.Background(), id)
+}
+
+// DeleteDocumentContext permanently deletes a document using the provided context
+func (c *Client) DeleteDocumentContext(ctx context.Context, id string) (json.RawMessage, error) {
 	request := GristRequest{
 		Path:   fmt.Sprintf("/api/docs/%s", id),
 		Method: http.MethodDelete,
 	}
 
-	return c.httpRequest(request)
+	return c.httpRequest(ctx, request)
+}
+
+// GetDocumentTyped gets the details of a document
+func (c *Client) GetDocumentTyped(id DocumentID) (Document, error) {
+	return c.GetDocumentTypedContext(context.Background(), id)
+}
+
+// GetDocumentTypedContext gets the details of a document using the provided context
+func (c *Client) GetDocumentTypedContext(ctx context.Context, id DocumentID) (Document, error) {
+	var d Document
+
+	resp, err := c.GetDocumentContext(ctx, string(id))
+	if err != nil {
+		return d, err
+	}
+
+	err = json.Unmarshal(resp, &d)
+
+	return d, err
+}
+
+// UpdateDocument renames or pins a document and returns the updated document
+func (c *Client) UpdateDocument(id DocumentID, update DocumentUpdate) (Document, error) {
+	return c.UpdateDocumentContext(context.Background(), id, update)
+}
+
+// UpdateDocumentContext renames or pins a document using the provided context
+func (c *Client) UpdateDocumentContext(ctx context.Context, id DocumentID, update DocumentUpdate) (Document, error) {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return Document{}, err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s", id),
+		Method: http.MethodPatch,
+		Data:   bytes.NewReader(data),
+	}
+
+	if _, err := c.httpRequest(ctx, request); err != nil {
+		return Document{}, err
+	}
+
+	return c.GetDocumentTypedContext(ctx, id)
+}
+
+// MoveDocument moves a document to another workspace and returns the moved document
+func (c *Client) MoveDocument(id DocumentID, workspace WorkspaceID) (Document, error) {
+	return c.MoveDocumentContext(context.Background(), id, workspace)
+}
+
+// MoveDocumentContext moves a document to another workspace using the provided context
+func (c *Client) MoveDocumentContext(ctx context.Context, id DocumentID, workspace WorkspaceID) (Document, error) {
+	data, err := json.Marshal(map[string]WorkspaceID{"workspace": workspace})
+	if err != nil {
+		return Document{}, err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/move", id),
+		Method: http.MethodPatch,
+		Data:   bytes.NewReader(data),
+	}
+
+	if _, err := c.httpRequest(ctx, request); err != nil {
+		return Document{}, err
+	}
+
+	return c.GetDocumentTypedContext(ctx, id)
+}
+
+// RemoveDocument moves a document to the trash where it can be restored with
+// RestoreDocument. If permanent is set the document is deleted instead.
+func (c *Client) RemoveDocument(id DocumentID, permanent bool) error {
+	return c.RemoveDocumentContext(context.Background(), id, permanent)
+}
+
+// RemoveDocumentContext moves a document to the trash using the provided context
+func (c *Client) RemoveDocumentContext(ctx context.Context, id DocumentID, permanent bool) error {
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/remove", id),
+		Method: http.MethodPost,
+	}
+
+	if permanent {
+		request.Query = url.Values{"permanent": []string{"1"}}
+	}
+
+	_, err := c.httpRequest(ctx, request)
+
+	return err
+}
+
+// RestoreDocument restores a document from the trash and returns it
+func (c *Client) RestoreDocument(id DocumentID) (Document, error) {
+	return c.RestoreDocumentContext(context.Background(), id)
+}
+
+// RestoreDocumentContext restores a document from the trash using the provided context
+func (c *Client) RestoreDocumentContext(ctx context.Context, id DocumentID) (Document, error) {
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/unremove", id),
+		Method: http.MethodPost,
+	}
+
+	if _, err := c.httpRequest(ctx, request); err != nil {
+		return Document{}, err
+	}
+
+	return c.GetDocumentTypedContext(ctx, id)
+}
+
+// ImportDocument creates a new document in the workspace from an uploaded file and returns its ID
+func (c *Client) ImportDocument(workspace WorkspaceID, doc DocumentImport) (DocumentID, error) {
+	return c.ImportDocumentContext(context.Background(), workspace, doc)
+}
+
+// ImportDocumentContext creates a new document from an uploaded file using the provided context
+func (c *Client) ImportDocumentContext(ctx context.Context, workspace WorkspaceID, doc DocumentImport) (DocumentID, error) {
+	if doc.Filename == "" || doc.Data == nil {
+		return "", errors.New("file name and data required to import document")
+	}
+
+	fields := map[string]string{
+		"workspaceId": strconv.Itoa(int(workspace)),
+	}
+
+	if doc.Name != "" {
+		fields["documentName"] = doc.Name
+	}
+
+	resp, err := c.upload(ctx, "/api/docs", fields, AttachmentFile{Name: doc.Filename, Data: doc.Data})
+	if err != nil {
+		return "", err
+	}
+
+	var id DocumentID
+	err = json.Unmarshal(resp, &id)
+
+	return id, err
+}
+
+// CopyDocument copies a document into a workspace and returns the ID of the copy
+func (c *Client) CopyDocument(id DocumentID, cp DocumentCopy) (DocumentID, error) {
+	return c.CopyDocumentContext(context.Background(), id, cp)
+}
+
+// CopyDocumentContext copies a document into a workspace using the provided context
+func (c *Client) CopyDocumentContext(ctx context.Context, id DocumentID, cp DocumentCopy) (DocumentID, error) {
+	if cp.Workspace == 0 || cp.Name == "" {
+		return "", errors.New("workspace and name required to copy document")
+	}
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return "", err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/copy", id),
+		Method: http.MethodPost,
+		Data:   bytes.NewReader(data),
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return "", err
+	}
+
+	var copyID DocumentID
+	err = json.Unmarshal(resp, &copyID)
+
+	return copyID, err
+}
+
+// ForkDocument creates a fork of a document
+func (c *Client) ForkDocument(id DocumentID) (Fork, error) {
+	return c.ForkDocumentContext(context.Background(), id)
+}
+
+// ForkDocumentContext creates a fork of a document using the provided context
+func (c *Client) ForkDocumentContext(ctx context.Context, id DocumentID) (Fork, error) {
+	var f Fork
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/fork", id),
+		Method: http.MethodPost,
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return f, err
+	}
+
+	err = json.Unmarshal(resp, &f)
+
+	return f, err
+}
+
+// ReplaceDocument replaces the contents of a document with another document or one of its snapshots
+func (c *Client) ReplaceDocument(id DocumentID, source DocumentReplace) error {
+	return c.ReplaceDocumentContext(context.Background(), id, source)
+}
+
+// ReplaceDocumentContext replaces the contents of a document using the provided context
+func (c *Client) ReplaceDocumentContext(ctx context.Context, id DocumentID, source DocumentReplace) error {
+	if (source.SourceDocID == "") == (source.SnapshotID == "") {
+		return errors.New("one of source document ID or snapshot ID required to replace document")
+	}
+
+	data, err := json.Marshal(source)
+	if err != nil {
+		return err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/replace", id),
+		Method: http.MethodPost,
+		Data:   bytes.NewReader(data),
+	}
+
+	_, err = c.httpRequest(ctx, request)
+
+	return err
 }
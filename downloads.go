@@ -0,0 +1,118 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+// Download streams an exported document. The caller must close it.
+type Download struct {
+	io.ReadCloser
+	// File name suggested by Grist in the Content-Disposition header
+	Filename    string
+	ContentType string
+}
+
+// DownloadOptions are the options for downloading a .grist file
+type DownloadOptions struct {
+	// Leave out the action history to reduce the file size
+	NoHistory bool
+	// Leave out the data, keeping only the structure of the document
+	Template bool
+}
+
+// DownloadDocument downloads the document as a .grist SQLite file
+func (c *Client) DownloadDocument(id DocumentID, opts DownloadOptions) (*Download, error) {
+	return c.DownloadDocumentContext(context.Background(), id, opts)
+}
+
+// DownloadDocumentContext downloads the document as a .grist SQLite file using the provided context
+func (c *Client) DownloadDocumentContext(ctx context.Context, id DocumentID, opts DownloadOptions) (*Download, error) {
+	q := url.Values{}
+
+	if opts.NoHistory {
+		q.Set("nohistory", "true")
+	}
+
+	if opts.Template {
+		q.Set("template", "true")
+	}
+
+	return c.download(ctx, fmt.Sprintf("/api/docs/%s/download", id), q)
+}
+
+// DownloadXLSX downloads every table of the document as an Excel workbook
+func (c *Client) DownloadXLSX(id DocumentID) (*Download, error) {
+	return c.DownloadXLSXContext(context.Background(), id)
+}
+
+// DownloadXLSXContext downloads every table of the document as an Excel workbook using the provided context
+func (c *Client) DownloadXLSXContext(ctx context.Context, id DocumentID) (*Download, error) {
+	return c.download(ctx, fmt.Sprintf("/api/docs/%s/download/xlsx", id), nil)
+}
+
+// DownloadCSV downloads a table as CSV
+func (c *Client) DownloadCSV(id DocumentID, table TableID) (*Download, error) {
+	return c.DownloadCSVContext(context.Background(), id, table)
+}
+
+// DownloadCSVContext downloads a table as CSV using the provided context
+func (c *Client) DownloadCSVContext(ctx context.Context, id DocumentID, table TableID) (*Download, error) {
+	q := url.Values{"tableId": []string{string(table)}}
+
+	return c.download(ctx, fmt.Sprintf("/api/docs/%s/download/csv", id), q)
+}
+
+// DownloadTableSchema downloads the frictionless data package schema of a table
+func (c *Client) DownloadTableSchema(id DocumentID, table TableID) (*Download, error) {
+	return c.DownloadTableSchemaContext(context.Background(), id, table)
+}
+
+// DownloadTableSchemaContext downloads the schema of a table using the provided context
+func (c *Client) DownloadTableSchemaContext(ctx context.Context, id DocumentID, table TableID) (*Download, error) {
+	q := url.Values{"tableId": []string{string(table)}}
+
+	return c.download(ctx, fmt.Sprintf("/api/docs/%s/download/table-schema", id), q)
+}
+
+func (c *Client) download(ctx context.Context, path string, q url.Values) (*Download, error) {
+	request := GristRequest{
+		Path:   path,
+		Method: http.MethodGet,
+		Query:  q,
+	}
+
+	resp, err := c.do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Download{
+		ReadCloser:  resp.Body,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		d.Filename = params["filename"]
+	}
+
+	return d, nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Filter restricts returned records to those whose columns match one of the
+// listed values. It encodes to the {"col": [values]} format Grist expects.
+type Filter map[string][]interface{}
+
+// FilterColumn is a column waiting for its values in a filter being built
+type FilterColumn struct {
+	filter Filter
+	column string
+}
+
+// Where starts a new filter on the column
+//
+//	gorist.Where("Status").In("open", "pending").And("Region").Eq("NE")
+func Where(column string) FilterColumn {
+	return Filter{}.And(column)
+}
+
+// And adds another column to the filter
+func (f Filter) And(column string) FilterColumn {
+	return FilterColumn{
+		filter: f,
+		column: column,
+	}
+}
+
+// In matches records where the column has any of the values
+func (c FilterColumn) In(values ...interface{}) Filter {
+	f := c.filter.copy()
+	f[c.column] = append([]interface{}{}, values...)
+
+	return f
+}
+
+// Eq matches records where the column equals the value
+func (c FilterColumn) Eq(value interface{}) Filter {
+	return c.In(value)
+}
+
+// Merge returns a filter containing the columns of both filters. Columns in
+// other replace the same columns in f.
+func (f Filter) Merge(other Filter) Filter {
+	merged := f.copy()
+	for k, v := range other {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// JSON returns the encoded filter for use with SetFilter, GetFilteredRecords or SetClientGlobalFilter,
+// or an error if one of the values can't be encoded
+func (f Filter) JSON() (json.RawMessage, error) {
+	data, err := json.Marshal(map[string][]interface{}(f))
+	if err != nil {
+		return nil, fmt.Errorf("encoding filter: %w", err)
+	}
+
+	return data, nil
+}
+
+// Validate checks that every filtered column exists in the table
+func (f Filter) Validate(table Table) error {
+	columns := map[string]bool{"id": true}
+	for _, v := range table.Columns {
+		columns[v.ID] = true
+	}
+
+	var missing []string
+	for k := range f {
+		if !columns[k] {
+			missing = append(missing, k)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("unknown filter columns in table %s: %s", table.ID, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func (f Filter) copy() Filter {
+	c := make(Filter, len(f))
+	for k, v := range f {
+		c[k] = v
+	}
+
+	return c
+}
+
+// WithFilter sets a request filter that is merged with the client global filter instead of overriding it.
+// If the filter can't be encoded the request fails with the encoding error.
+func WithFilter(f Filter) GristRequestOpt {
+	return func(r *GristRequest) {
+		filter, err := f.JSON()
+		if err != nil {
+			r.err = err
+			return
+		}
+
+		r.Filter = filter
+		r.MergeGlobalFilter = true
+	}
+}
+
+// GetRecordsWhere gets the records in a table matching the filter merged with the client global filter
+func (c *Client) GetRecordsWhere(document DocumentID, table TableID, f Filter) (json.RawMessage, error) {
+	return c.GetRecordsWhereContext(context.Background(), document, table, f)
+}
+
+// GetRecordsWhereContext is GetRecordsWhere using the provided context
+func (c *Client) GetRecordsWhereContext(ctx context.Context, document DocumentID, table TableID, f Filter) (json.RawMessage, error) {
+	return c.GetRecordsWithOptionsContext(ctx,
+		SetDocument(document),
+		SetTable(table),
+		WithFilter(f),
+	)
+}
+
+// ValidateFilter fetches the columns of the table and checks that every filtered column exists
+func (c *Client) ValidateFilter(document DocumentID, table TableID, f Filter) error {
+	return c.ValidateFilterContext(context.Background(), document, table, f)
+}
+
+// ValidateFilterContext is ValidateFilter using the provided context
+func (c *Client) ValidateFilterContext(ctx context.Context, document DocumentID, table TableID, f Filter) error {
+	t := Table{ID: table}
+
+	columns, err := c.GetColumnsTypedContext(ctx, document, t)
+	if err != nil {
+		return err
+	}
+
+	t.Columns = columns
+
+	return f.Validate(t)
+}
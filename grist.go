@@ -15,10 +15,15 @@
 package gorist
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 type ClientOpt func(*Client)
@@ -41,7 +46,57 @@ type GristRequest struct {
 	Table    TableID
 	// Overrides a client global filter
 	Filter json.RawMessage
-	Data   io.Reader
+	// Merge Filter into the client global filter instead of overriding it
+	MergeGlobalFilter bool
+	// Don't send the client global filter, for endpoints that don't list table records
+	SkipGlobalFilter bool
+	// Sort order of returned records
+	Sort []Sort
+	// Maximum number of records returned. Zero means no limit
+	Limit int
+	// Include hidden columns such as manualSort and gristHelper columns
+	Hidden bool
+	// Send sort and limit as X-Sort and X-Limit headers instead of query parameters
+	SortLimitHeaders bool
+	// Additional query parameters sent with the request
+	Query url.Values
+	// Defaults to application/json
+	ContentType string
+	Data        io.Reader
+	// Error from an option, such as a filter that can't be encoded. It is returned instead of sending the request
+	err error
+}
+
+// Sort describes the ordering of records by a single column
+type Sort struct {
+	Column     string
+	Descending bool
+	// Compare strings containing numbers by their numeric value
+	NaturalSort bool
+	// Place empty values before other values
+	EmptyFirst bool
+}
+
+func (s Sort) String() string {
+	col := s.Column
+	if s.Descending {
+		col = "-" + col
+	}
+
+	var flags []string
+	if s.NaturalSort {
+		flags = append(flags, "naturalSort")
+	}
+
+	if s.EmptyFirst {
+		flags = append(flags, "emptyFirst")
+	}
+
+	if len(flags) > 0 {
+		col = fmt.Sprintf("%s:%s", col, strings.Join(flags, ";"))
+	}
+
+	return col
 }
 
 type GristRequestOpt func(*GristRequest)
@@ -64,6 +119,34 @@ func SetFilter(f json.RawMessage) GristRequestOpt {
 	}
 }
 
+// SetSort sets the order of returned records
+func SetSort(s ...Sort) GristRequestOpt {
+	return func(r *GristRequest) {
+		r.Sort = s
+	}
+}
+
+// SetLimit sets the maximum number of returned records
+func SetLimit(l int) GristRequestOpt {
+	return func(r *GristRequest) {
+		r.Limit = l
+	}
+}
+
+// IncludeHidden returns hidden columns along with the regular columns
+func IncludeHidden() GristRequestOpt {
+	return func(r *GristRequest) {
+		r.Hidden = true
+	}
+}
+
+// UseSortLimitHeaders sends the sort and limit options as X-Sort and X-Limit headers
+func UseSortLimitHeaders() GristRequestOpt {
+	return func(r *GristRequest) {
+		r.SortLimitHeaders = true
+	}
+}
+
 func NewClient(opts ...ClientOpt) *Client {
 	c := &Client{}
 
@@ -106,26 +189,143 @@ func SetClientGlobalFilter(f json.RawMessage) ClientOpt {
 	}
 }
 
-func (c *Client) httpRequest(request GristRequest) (json.RawMessage, error) {
+func (c *Client) httpRequest(ctx context.Context, request GristRequest) (json.RawMessage, error) {
+	resp, err := c.do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+// upload sends the form fields and files as a multipart POST. The files are
+// streamed rather than buffered in memory.
+func (c *Client) upload(ctx context.Context, path string, fields map[string]string, files ...AttachmentFile) (json.RawMessage, error) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		for k, v := range fields {
+			if err := mw.WriteField(k, v); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+
+		for _, v := range files {
+			part, err := mw.CreateFormFile("upload", v.Name)
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+
+			if _, err := io.Copy(part, v.Data); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+
+		pw.CloseWithError(mw.Close())
+	}()
+
+	request := GristRequest{
+		Path:        path,
+		Method:      http.MethodPost,
+		ContentType: mw.FormDataContentType(),
+		Data:        pr,
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	// unblock the writer if the request failed before reading the body
+	pr.Close()
+
+	return resp, err
+}
+
+// do sends the request and returns the response for the caller to read and
+// close. Responses with an error status are returned as a GristError.
+func (c *Client) do(ctx context.Context, request GristRequest) (*http.Response, error) {
+	if request.err != nil {
+		return nil, request.err
+	}
+
 	url := fmt.Sprintf("%s%s", c.URL, request.Path)
 	token := fmt.Sprintf("Bearer %s", c.Token)
 
-	req, err := http.NewRequest(request.Method, url, request.Data)
+	req, err := http.NewRequestWithContext(ctx, request.Method, url, request.Data)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Content-Type", "application/json")
+	contentType := request.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	req.Header.Add("Content-Type", contentType)
 
 	q := req.URL.Query()
 
-	if c.GlobalFilter != nil {
-		q.Add("filter", string(c.GlobalFilter))
+	global := c.GlobalFilter
+	if request.SkipGlobalFilter {
+		global = nil
+	}
+
+	if global != nil {
+		q.Add("filter", string(global))
 	}
 
 	if request.Filter != nil {
+		filter := request.Filter
+		if request.MergeGlobalFilter && global != nil {
+			var g, f Filter
+			if err := json.Unmarshal(global, &g); err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(request.Filter, &f); err != nil {
+				return nil, err
+			}
+			filter, err = g.Merge(f).JSON()
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		q.Del("filter")
-		q.Add("filter", string(request.Filter))
+		q.Add("filter", string(filter))
+	}
+
+	if len(request.Sort) > 0 {
+		sort := make([]string, 0, len(request.Sort))
+		for _, v := range request.Sort {
+			sort = append(sort, v.String())
+		}
+
+		if request.SortLimitHeaders {
+			req.Header.Add("X-Sort", strings.Join(sort, ","))
+		} else {
+			q.Add("sort", strings.Join(sort, ","))
+		}
+	}
+
+	if request.Limit > 0 {
+		if request.SortLimitHeaders {
+			req.Header.Add("X-Limit", strconv.Itoa(request.Limit))
+		} else {
+			q.Add("limit", strconv.Itoa(request.Limit))
+		}
+	}
+
+	if request.Hidden {
+		q.Add("hidden", "true")
+	}
+
+	for k, values := range request.Query {
+		for _, v := range values {
+			q.Add(k, v)
+		}
 	}
 	req.URL.RawQuery = q.Encode()
 
@@ -136,21 +336,21 @@ func (c *Client) httpRequest(request GristRequest) (json.RawMessage, error) {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
 
-	if resp.StatusCode != 200 {
 		return nil, GristError{
 			StatusCode: resp.StatusCode,
 			Details:    string(body),
 		}
 	}
 
-	return body, nil
+	return resp, nil
 }
 
 type GristError struct {
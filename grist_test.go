@@ -16,12 +16,17 @@ package gorist
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 )
 
 type GristTest struct {
@@ -33,10 +38,10 @@ type GristTest struct {
 }
 
 type Data struct {
-	Records []Record `json:"records"`
+	Records []TestRecord `json:"records"`
 }
 
-type Record struct {
+type TestRecord struct {
 	ID     int         `json:"id"`
 	Fields []TestField `json:"fields"`
 }
@@ -76,6 +81,38 @@ func getHandler(gt GristTest) http.HandlerFunc {
 	}
 }
 
+// callRecorder is a test handler that records each request as "METHOD /path?query body"
+// and replies with the response registered for "METHOD /path", or an empty body
+type callRecorder struct {
+	calls     []string
+	responses map[string]string
+}
+
+func (rec *callRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	call := fmt.Sprintf("%s %s", r.Method, r.URL.RequestURI())
+	if len(data) > 0 {
+		call = fmt.Sprintf("%s %s", call, data)
+	}
+	rec.calls = append(rec.calls, call)
+
+	w.Write([]byte(rec.responses[fmt.Sprintf("%s %s", r.Method, r.URL.Path)]))
+}
+
+// check compares the recorded calls with the expected calls
+func (rec *callRecorder) check(t *testing.T, expected ...string) {
+	t.Helper()
+
+	if !reflect.DeepEqual(rec.calls, expected) {
+		t.Errorf("expected \n%v\nbut got \n%v", expected, rec.calls)
+	}
+}
+
 func postHandler(gt GristTest) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var data Data
@@ -111,7 +148,7 @@ func TestGetDocument(t *testing.T) {
 			name: "document request",
 			key:  "test", document: DocumentID("document1"),
 			data: Data{
-				Records: []Record{
+				Records: []TestRecord{
 					{
 						ID: 1,
 						Fields: []TestField{
@@ -123,7 +160,7 @@ func TestGetDocument(t *testing.T) {
 				},
 			},
 			expected: Data{
-				Records: []Record{
+				Records: []TestRecord{
 					{
 						ID: 1,
 						Fields: []TestField{
@@ -139,7 +176,7 @@ func TestGetDocument(t *testing.T) {
 			name: "filtered document request",
 			key:  "test", document: "document1",
 			data: Data{
-				Records: []Record{
+				Records: []TestRecord{
 					{
 						ID: 1,
 						Fields: []TestField{
@@ -159,7 +196,7 @@ func TestGetDocument(t *testing.T) {
 				},
 			},
 			expected: Data{
-				Records: []Record{
+				Records: []TestRecord{
 					{
 						ID: 1,
 						Fields: []TestField{
@@ -230,7 +267,7 @@ func TestCreateRecord(t *testing.T) {
 		{
 			name: "normal post",
 			data: Data{
-				Records: []Record{
+				Records: []TestRecord{
 					{
 						Fields: []TestField{
 							{
@@ -241,7 +278,7 @@ func TestCreateRecord(t *testing.T) {
 				},
 			},
 			expected: Data{
-				Records: []Record{
+				Records: []TestRecord{
 					{
 						ID: 1,
 					},
@@ -291,3 +328,1412 @@ func TestCreateRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRecordsContextCanceled(t *testing.T) {
+	s := httptest.NewServer(getHandler(GristTest{document: "document1", key: "test"}))
+	defer s.Close()
+
+	c := NewClient(
+		SetURL(s.URL),
+		SetAPIKey("test"),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.GetRecordsContext(ctx, "document1", "test")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled error but got %v", err)
+	}
+}
+
+type typedFields struct {
+	Name string `json:"name"`
+	Open bool   `json:"open"`
+}
+
+func TestTypedRecords(t *testing.T) {
+	expected := []Record[typedFields]{
+		{ID: 1, Fields: typedFields{Name: "one", Open: true}},
+		{ID: 2, Fields: typedFields{Name: "two"}},
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(Records[typedFields]{Records: expected})
+			return
+		}
+
+		var data Records[typedFields]
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp := Records[struct{}]{}
+		for i, v := range data.Records {
+			if v.ID != 0 {
+				http.Error(w, "id must not be sent on create", http.StatusBadRequest)
+				return
+			}
+			resp.Records = append(resp.Records, Record[struct{}]{ID: i + 10})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	records, err := GetRecordsAs[typedFields](c, "document1", "test")
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected \n%#v\nbut got \n%#v", expected, records)
+	}
+
+	ids, err := CreateRecordsFrom(c, "document1", "test", typedFields{Name: "a"}, typedFields{Name: "b"})
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if !reflect.DeepEqual(ids, []int{10, 11}) {
+		t.Errorf("expected ids [10 11] but got %v", ids)
+	}
+}
+
+func TestCellCodec(t *testing.T) {
+	type fields struct {
+		IDs     []int       `json:"ids"`
+		Choices ChoiceList  `json:"choices"`
+		Tags    *ChoiceList `json:"tags"`
+		Refs    RefList     `json:"refs"`
+		Ref     Ref         `json:"ref"`
+		Date    GristDate   `json:"date"`
+	}
+
+	data := []byte(`{"id":3,"fields":{"ids":["L",1,2],"choices":["L","L","b"],"tags":["L","L","b"],"refs":["r","People",[4,5]],"ref":["R","People",7],"date":["d",1690000000]}}`)
+
+	var r Record[fields]
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("error unmarshaling: %v", err)
+	}
+
+	expected := Record[fields]{
+		ID: 3,
+		Fields: fields{
+			IDs:     []int{1, 2},
+			Choices: ChoiceList{"L", "b"},
+			Tags:    &ChoiceList{"L", "b"},
+			Refs:    RefList{4, 5},
+			Ref:     Ref{Table: "People", ID: 7},
+			Date:    GristDate{time.Unix(1690000000, 0).UTC()},
+		},
+	}
+
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("expected \n%#v\nbut got \n%#v", expected, r)
+	}
+
+	out, err := json.Marshal(r.Fields.Refs)
+	if err != nil {
+		t.Fatalf("error marshaling: %v", err)
+	}
+
+	if string(out) != `["L",4,5]` {
+		t.Errorf("expected encoded list but got %s", out)
+	}
+
+	var l RefList
+	err = json.Unmarshal([]byte(`["E","ZeroDivisionError","division by zero"]`), &l)
+	var cellErr *CellError
+	if !errors.As(err, &cellErr) || cellErr.Name != "ZeroDivisionError" {
+		t.Errorf("expected cell error but got %v", err)
+	}
+
+	var d GristDate
+	if err := json.Unmarshal([]byte(`["d",-20000000000]`), &d); err != nil || d.Year() != 1336 {
+		t.Errorf("expected date in 1336 but got %v, %v", d, err)
+	}
+
+	v, err := DecodeCell(json.RawMessage(` ["R","People",1]`))
+	if err != nil || v != (Ref{Table: "People", ID: 1}) {
+		t.Errorf("expected reference but got %v, %v", v, err)
+	}
+
+	var ref Ref
+	if err := ref.UnmarshalJSON(nil); err == nil {
+		t.Errorf("expected error for empty reference")
+	}
+}
+
+func TestRecordListEncoding(t *testing.T) {
+	type fields struct {
+		IDs     []int      `json:"ids"`
+		Choices ChoiceList `json:"choices"`
+		Tags    []string   `json:"tags,omitempty"`
+		Name    string     `json:"name"`
+	}
+
+	tests := []struct {
+		name     string
+		record   interface{}
+		expected string
+	}{
+		{
+			name:     "record",
+			record:   Record[fields]{Fields: fields{IDs: []int{1, 2}, Choices: ChoiceList{"a"}, Name: "one"}},
+			expected: `{"fields":{"choices":["L","a"],"ids":["L",1,2],"name":"one"}}`,
+		},
+		{
+			name:     "update",
+			record:   RecordUpdate{ID: 3, Fields: fields{IDs: []int{4}}},
+			expected: `{"id":3,"fields":{"choices":["L"],"ids":["L",4],"name":""}}`,
+		},
+		{
+			name:     "upsert",
+			record:   RecordUpsert{Require: map[string]string{"name": "one"}, Fields: &fields{Tags: []string{"x"}}},
+			expected: `{"require":{"name":"one"},"fields":{"choices":["L"],"ids":null,"name":"","tags":["L","x"]}}`,
+		},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			data, err := json.Marshal(v.record)
+			if err != nil {
+				t.Fatalf("error marshaling: %v", err)
+			}
+
+			if string(data) != v.expected {
+				t.Errorf("expected %s but got %s", v.expected, data)
+			}
+		})
+	}
+
+	var decoded Record[fields]
+	if err := json.Unmarshal([]byte(tests[0].expected), &decoded); err != nil {
+		t.Fatalf("error unmarshaling: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, tests[0].record) {
+		t.Errorf("expected round trip to give %#v but got %#v", tests[0].record, decoded)
+	}
+}
+
+func TestUpsertRecords(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		q := r.URL.Query()
+		if q.Get("onmany") != "all" || q.Get("noadd") != "true" || q.Has("noupdate") {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		var data struct {
+			Records []struct {
+				Require map[string]string `json:"require"`
+				Fields  typedFields       `json:"fields"`
+			} `json:"records"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil || len(data.Records) != 1 || data.Records[0].Require["name"] != "one" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	}))
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	err := c.UpsertRecords("document1", "test", UpsertOptions{OnMany: OnManyAll, NoAdd: true}, RecordUpsert{
+		Require: map[string]string{"name": "one"},
+		Fields:  typedFields{Name: "one", Open: true},
+	})
+	if err != nil {
+		t.Errorf("expected no errors but got %v", err)
+	}
+
+	if err := c.UpdateRecords("document1", "test", RecordUpdate{Fields: typedFields{}}); err == nil {
+		t.Errorf("expected error for missing record id")
+	}
+}
+
+func TestDeleteRecords(t *testing.T) {
+	var deleted []int
+	var legacy int
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/docs/document1/tables/test/records/delete":
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		case "/api/docs/document1/tables/test/data/delete":
+			legacy++
+		default:
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		var ids []int
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil || len(ids) > deleteChunkSize {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		deleted = append(deleted, ids...)
+	}))
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	ids := make([]int, deleteChunkSize+1)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	if err := c.DeleteRecords("document1", "test", ids...); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if legacy != 2 {
+		t.Errorf("expected 2 chunked requests but got %d", legacy)
+	}
+
+	if !reflect.DeepEqual(deleted, ids) {
+		t.Errorf("expected all ids to be deleted but got %d", len(deleted))
+	}
+}
+
+func TestSortLimitOptions(t *testing.T) {
+	tt := []struct {
+		name    string
+		opts    []GristRequestOpt
+		headers bool
+	}{
+		{name: "query parameters"},
+		{name: "headers", opts: []GristRequestOpt{UseSortLimitHeaders()}, headers: true},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				sort, limit := q.Get("sort"), q.Get("limit")
+				if v.headers {
+					sort, limit = r.Header.Get("X-Sort"), r.Header.Get("X-Limit")
+				}
+
+				if sort != "-age,name:naturalSort;emptyFirst" || limit != "10" || q.Get("hidden") != "true" {
+					http.Error(w, fmt.Sprintf("sort: %s, limit: %s", sort, limit), http.StatusBadRequest)
+					return
+				}
+
+				w.Write([]byte(`{"records":[]}`))
+			}))
+			defer s.Close()
+
+			c := NewClient(SetURL(s.URL))
+
+			opts := append([]GristRequestOpt{
+				SetDocument("document1"),
+				SetTable("test"),
+				SetSort(Sort{Column: "age", Descending: true}, Sort{Column: "name", NaturalSort: true, EmptyFirst: true}),
+				SetLimit(10),
+				IncludeHidden(),
+			}, v.opts...)
+
+			if _, err := c.GetRecordsWithOptions(opts...); err != nil {
+				t.Errorf("expected no errors but got %v", err)
+			}
+		})
+	}
+}
+
+func TestFilterBuilder(t *testing.T) {
+	f := Where("Status").In("open", "pending").And("Region").Eq("NE")
+
+	data, err := f.JSON()
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if string(data) != `{"Region":["NE"],"Status":["open","pending"]}` {
+		t.Errorf("unexpected filter %s", data)
+	}
+
+	table := Table{ID: "Claims", Columns: []Column{{ID: "Status"}}}
+	if err := f.Validate(table); err == nil {
+		t.Errorf("expected error for missing Region column")
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := `{"Region":["NE"],"Status":["open","pending"],"Tenant":["acme"]}`
+		if u := r.URL.Query().Get("filter"); u != expected {
+			http.Error(w, u, http.StatusBadRequest)
+			return
+		}
+
+		w.Write([]byte(`{"records":[]}`))
+	}))
+	defer s.Close()
+
+	global, err := Where("Tenant").Eq("acme").And("Region").Eq("SE").JSON()
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	c := NewClient(
+		SetURL(s.URL),
+		SetClientGlobalFilter(global),
+	)
+
+	if _, err := c.GetRecordsWhere("document1", "Claims", f); err != nil {
+		t.Errorf("expected no errors but got %v", err)
+	}
+
+	var unsupported *json.UnsupportedValueError
+	if _, err := c.GetRecordsWhere("document1", "Claims", Where("Amount").Eq(math.NaN())); !errors.As(err, &unsupported) {
+		t.Errorf("expected encoding error for NaN filter value but got %v", err)
+	}
+}
+
+func TestQuerySQL(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var q struct {
+			SQL     string        `json:"sql"`
+			Args    []interface{} `json:"args"`
+			Timeout int           `json:"timeout"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&q); err != nil || len(q.Args) != 1 || q.Timeout != 0 {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		fmt.Fprintf(w, `{"statement":%q,"records":[{"fields":{"name":"one","open":1}},{"fields":{"name":"two","open":0}}]}`, q.SQL)
+	}))
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	res, err := c.QuerySQL("document1", "select name, open from Claims where region = ?", "NE")
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if !reflect.DeepEqual(res.Columns, []string{"name", "open"}) || len(res.Rows) != 2 {
+		t.Errorf("unexpected result %#v", res)
+	}
+
+	type row struct {
+		Name string `json:"name"`
+		Open int    `json:"open"`
+	}
+
+	rows, err := ScanSQL[row](res)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if !reflect.DeepEqual(rows, []row{{Name: "one", Open: 1}, {Name: "two"}}) {
+		t.Errorf("unexpected rows %#v", rows)
+	}
+}
+
+func TestAttachments(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/docs/document1/attachments":
+			if r.Method == http.MethodGet {
+				if f := r.URL.Query().Get("filter"); f != "" && f != `{"fileName":["claim1.pdf"]}` {
+					http.Error(w, f, http.StatusBadRequest)
+					return
+				}
+
+				w.Write([]byte(`{"records":[{"id":1,"fields":{"fileName":"claim1.pdf","fileSize":3}}]}`))
+				return
+			}
+
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			var ids []int
+			for i, v := range r.MultipartForm.File["upload"] {
+				if v.Filename != fmt.Sprintf("claim%d.pdf", i+1) {
+					http.Error(w, v.Filename, http.StatusBadRequest)
+					return
+				}
+				ids = append(ids, i+1)
+			}
+			json.NewEncoder(w).Encode(ids)
+		case "/api/docs/document1/attachments/1/download":
+			w.Write([]byte("contents"))
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL), SetClientGlobalFilter(json.RawMessage(`{"Tenant":["acme"]}`)))
+
+	ids, err := c.UploadAttachments("document1",
+		AttachmentFile{Name: "claim1.pdf", Data: bytes.NewReader([]byte("one"))},
+		AttachmentFile{Name: "claim2.pdf", Data: bytes.NewReader([]byte("two"))},
+	)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("expected ids [1 2] but got %v", ids)
+	}
+
+	if _, err := c.UploadAttachments("document1", AttachmentFile{Name: "claim3.pdf"}); err == nil {
+		t.Errorf("expected error for attachment without data")
+	}
+
+	list, err := c.ListAttachments("document1", SetFilter(json.RawMessage(`{"fileName":["claim1.pdf"]}`)))
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if len(list) != 1 || list[0].Fields.FileName != "claim1.pdf" {
+		t.Errorf("unexpected attachments %#v", list)
+	}
+
+	if _, err := c.ListAttachments("document1"); err != nil {
+		t.Errorf("expected global filter to be skipped but got %v", err)
+	}
+
+	rc, err := c.DownloadAttachment("document1", 1)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil || string(data) != "contents" {
+		t.Errorf("unexpected download %q: %v", data, err)
+	}
+
+	if _, err := c.DownloadAttachment("document1", 2); err == nil {
+		t.Errorf("expected error for missing attachment")
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var received []Record[typedFields]
+
+	h := NewWebhookHandler(SetWebhookSecret("shh"))
+	HandleTable(h, "Claims", func(ctx context.Context, records []Record[typedFields]) error {
+		received = records
+		if len(records) > 1 {
+			return errors.New("too many records")
+		}
+		return nil
+	})
+
+	tt := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{name: "delivery", path: "/hooks/Claims?secret=shh", body: `[{"id":4,"name":"one","open":true}]`, status: http.StatusOK},
+		{name: "bad secret", path: "/hooks/Claims?secret=nope", body: `[]`, status: http.StatusUnauthorized},
+		{name: "unknown table", path: "/hooks/Other?secret=shh", body: `[]`, status: http.StatusNotFound},
+		{name: "bad payload", path: "/hooks/Claims?secret=shh", body: `{}`, status: http.StatusBadRequest},
+		{name: "callback error", path: "/hooks/Claims?secret=shh", body: `[{"id":1},{"id":2}]`, status: http.StatusInternalServerError},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, v.path, bytes.NewReader([]byte(v.body))))
+
+			if w.Code != v.status {
+				t.Errorf("expected status %d but got %d: %s", v.status, w.Code, w.Body)
+			}
+		})
+	}
+
+	expected := []Record[typedFields]{{ID: 1}, {ID: 2}}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected \n%#v\nbut got \n%#v", expected, received)
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	rec := &callRecorder{
+		responses: map[string]string{
+			"POST /api/docs/document1/webhooks": `{"webhooks":[{"id":"w1"},{"id":"w2"}]}`,
+		},
+	}
+
+	s := httptest.NewServer(rec)
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	hook := WebhookFields{
+		URL:        "https://example.com/hook",
+		TableID:    "Claims",
+		EventTypes: []WebhookEventType{WebhookAdd, WebhookUpdate},
+	}
+
+	if _, err := c.CreateWebhooks("document1", hook, WebhookFields{URL: hook.URL, TableID: "Claims"}); err == nil {
+		t.Errorf("expected error for webhook without event types")
+	}
+
+	ids, err := c.CreateWebhooks("document1", hook, hook)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if !reflect.DeepEqual(ids, []WebhookID{"w1", "w2"}) {
+		t.Errorf("unexpected webhook IDs %v", ids)
+	}
+
+	if err := c.ClearWebhookQueue("document1", ids...); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if err := c.ClearWebhookQueue("document1"); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	fields := `{"fields":{"url":"https://example.com/hook","eventTypes":["add","update"],"tableId":"Claims"}}`
+
+	rec.check(t,
+		fmt.Sprintf(`POST /api/docs/document1/webhooks {"webhooks":[%s,%s]}`, fields, fields),
+		"DELETE /api/docs/document1/webhooks/queue/w1",
+		"DELETE /api/docs/document1/webhooks/queue/w2",
+		"DELETE /api/docs/document1/webhooks/queue",
+	)
+}
+
+func TestAccessDelta(t *testing.T) {
+	none := NoAccess
+	delta := AccessDelta{
+		Users: map[string]Role{
+			"new@example.com": Editors,
+			"old@example.com": NoAccess,
+		},
+		MaxInheritedRole: &none,
+	}
+
+	data, err := json.Marshal(delta)
+	if err != nil {
+		t.Fatalf("error marshaling: %v", err)
+	}
+
+	expected := `{"delta":{"maxInheritedRole":null,"users":{"new@example.com":"editors","old@example.com":null}}}`
+	if string(data) != expected {
+		t.Errorf("expected %s but got %s", expected, data)
+	}
+}
+
+func TestAccessEndpoints(t *testing.T) {
+	users := `{"users":[{"id":5,"name":"Ann","email":"ann@example.com","access":"owners"}]}`
+
+	rec := &callRecorder{
+		responses: map[string]string{
+			"GET /api/orgs/current/access":   users,
+			"GET /api/workspaces/7/access":   `{"maxInheritedRole":"viewers","users":[]}`,
+			"GET /api/docs/document1/access": users,
+		},
+	}
+
+	s := httptest.NewServer(rec)
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+	delta := AccessDelta{Users: map[string]Role{"bob@example.com": Viewers}}
+
+	org, err := c.GetOrgAccess(CurrentOrg)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if len(org.Users) != 1 || org.Users[0].Access != Owners {
+		t.Errorf("unexpected org access %#v", org)
+	}
+
+	if err := c.UpdateOrgAccess(CurrentOrg, delta); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	ws, err := c.GetWorkspaceAccess(7)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if ws.MaxInheritedRole != Viewers {
+		t.Errorf("unexpected workspace access %#v", ws)
+	}
+
+	if err := c.UpdateWorkspaceAccess(7, delta); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if _, err := c.GetDocumentAccess("document1"); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if err := c.UpdateDocumentAccess("document1", delta); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	body := `{"delta":{"users":{"bob@example.com":"viewers"}}}`
+
+	rec.check(t,
+		"GET /api/orgs/current/access",
+		"PATCH /api/orgs/current/access "+body,
+		"GET /api/workspaces/7/access",
+		"PATCH /api/workspaces/7/access "+body,
+		"GET /api/docs/document1/access",
+		"PATCH /api/docs/document1/access "+body,
+	)
+}
+
+func TestOrgs(t *testing.T) {
+	rec := &callRecorder{
+		responses: map[string]string{
+			"GET /api/orgs":                    `[{"id":1,"name":"Personal","domain":"docs-1"},{"id":2,"name":"Claims","domain":"claims"}]`,
+			"GET /api/orgs/current":            `{"id":2,"name":"Claims","domain":"claims","owner":{"id":5,"name":"Ann"}}`,
+			"GET /api/orgs/current/workspaces": `[{"id":7,"name":"Home","docs":[{"id":"document1","name":"claims"}]}]`,
+		},
+	}
+
+	s := httptest.NewServer(rec)
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	orgs, err := c.ListOrgsTyped()
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if len(orgs) != 2 || orgs[1].ID != 2 || orgs[1].Domain != "claims" {
+		t.Errorf("unexpected orgs %#v", orgs)
+	}
+
+	org, err := c.GetOrgTyped(CurrentOrg)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if org.ID != 2 || org.Owner.Name != "Ann" {
+		t.Errorf("unexpected org %#v", org)
+	}
+
+	workspaces, err := c.GetOrgWorkspacesAndDocumentsTyped(CurrentOrg)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if len(workspaces) != 1 || workspaces[0].ID != 7 || len(workspaces[0].Docs) != 1 || workspaces[0].Docs[0].ID != "document1" {
+		t.Errorf("unexpected workspaces %#v", workspaces)
+	}
+
+	if err := c.UpdateOrg("2", OrgUpdate{Name: "Claims Team"}); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if err := c.UpdateOrg("2", OrgUpdate{Domain: "claims-team"}); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if err := c.DeleteOrg("2"); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	rec.check(t,
+		"GET /api/orgs",
+		"GET /api/orgs/current",
+		"GET /api/orgs/current/workspaces",
+		`PATCH /api/orgs/2 {"name":"Claims Team"}`,
+		`PATCH /api/orgs/2 {"domain":"claims-team"}`,
+		"DELETE /api/orgs/2",
+	)
+}
+
+func TestWorkspaceSoftDelete(t *testing.T) {
+	rec := &callRecorder{
+		responses: map[string]string{
+			"GET /api/workspaces/5": `{"id":5,"name":"claims"}`,
+		},
+	}
+
+	s := httptest.NewServer(rec)
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	if err := c.RemoveWorkspace(5, false); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	ws, err := c.RestoreWorkspace(5)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if ws.ID != 5 || ws.Name != "claims" {
+		t.Errorf("unexpected workspace %#v", ws)
+	}
+
+	if err := c.RemoveWorkspace(5, true); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	rec.check(t,
+		"POST /api/workspaces/5/remove",
+		"POST /api/workspaces/5/unremove",
+		"GET /api/workspaces/5",
+		"POST /api/workspaces/5/remove?permanent=1",
+	)
+}
+
+func TestDocumentLifecycle(t *testing.T) {
+	rec := &callRecorder{
+		responses: map[string]string{
+			"GET /api/docs/document1": `{"id":"document1","name":"claims","workspace":{"id":7}}`,
+		},
+	}
+
+	s := httptest.NewServer(rec)
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	if _, err := c.UpdateDocument("document1", DocumentUpdate{Name: "claims"}); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	doc, err := c.MoveDocument("document1", 7)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if doc.ID != "document1" || doc.Workspace.ID != 7 {
+		t.Errorf("unexpected document %#v", doc)
+	}
+
+	if err := c.RemoveDocument("document1", false); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if _, err := c.RestoreDocument("document1"); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if err := c.RemoveDocument("document1", true); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	rec.check(t,
+		`PATCH /api/docs/document1 {"name":"claims"}`,
+		"GET /api/docs/document1",
+		`PATCH /api/docs/document1/move {"workspace":7}`,
+		"GET /api/docs/document1",
+		"POST /api/docs/document1/remove",
+		"POST /api/docs/document1/unremove",
+		"GET /api/docs/document1",
+		"POST /api/docs/document1/remove?permanent=1",
+	)
+}
+
+func TestDocumentCopyForkReplace(t *testing.T) {
+	rec := &callRecorder{
+		responses: map[string]string{
+			"POST /api/docs/document1/copy": `"document2"`,
+			"POST /api/docs/document1/fork": `{"forkId":"f1","docId":"document1~f1","urlId":"document1~f1"}`,
+		},
+	}
+
+	s := httptest.NewServer(rec)
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	if _, err := c.CopyDocument("document1", DocumentCopy{Name: "copy"}); err == nil {
+		t.Errorf("expected error for copy without workspace")
+	}
+
+	id, err := c.CopyDocument("document1", DocumentCopy{Workspace: 7, Name: "copy", AsTemplate: true})
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if id != "document2" {
+		t.Errorf("expected document2 but got %s", id)
+	}
+
+	fork, err := c.ForkDocument("document1")
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if fork.ForkID != "f1" || fork.DocID != "document1~f1" {
+		t.Errorf("unexpected fork %#v", fork)
+	}
+
+	for _, v := range []DocumentReplace{{}, {SourceDocID: "document2", SnapshotID: "s1"}} {
+		if err := c.ReplaceDocument("document1", v); err == nil {
+			t.Errorf("expected error for replace source %#v", v)
+		}
+	}
+
+	if err := c.ReplaceDocument("document1", DocumentReplace{SnapshotID: "s1"}); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	rec.check(t,
+		`POST /api/docs/document1/copy {"workspaceId":7,"documentName":"copy","asTemplate":true}`,
+		"POST /api/docs/document1/fork",
+		`POST /api/docs/document1/replace {"snapshotId":"s1"}`,
+	)
+}
+
+func TestDownloadCSV(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/docs/document1/download/csv" || r.URL.Query().Get("tableId") != "Claims" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="Claims.csv"`)
+		w.Write([]byte("name,open\none,true\n"))
+	}))
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	d, err := c.DownloadCSV("document1", "Claims")
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+	defer d.Close()
+
+	data, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("error reading download: %v", err)
+	}
+
+	if d.Filename != "Claims.csv" || string(data) != "name,open\none,true\n" {
+		t.Errorf("unexpected download %q: %q", d.Filename, data)
+	}
+}
+
+func TestImportDocument(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		files := r.MultipartForm.File["upload"]
+		if r.FormValue("workspaceId") != "7" || r.FormValue("documentName") != "Acme" || len(files) != 1 || files[0].Filename != "template.grist" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		w.Write([]byte(`"newDoc"`))
+	}))
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	id, err := c.ImportDocument(7, DocumentImport{
+		Name:     "Acme",
+		Filename: "template.grist",
+		Data:     bytes.NewReader([]byte("sqlite")),
+	})
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if id != "newDoc" {
+		t.Errorf("expected document newDoc but got %s", id)
+	}
+}
+
+func TestRemoveSnapshots(t *testing.T) {
+	rec := &callRecorder{
+		responses: map[string]string{
+			"POST /api/docs/document1/snapshots/remove": `{"snapshotIds":["s1","s2"]}`,
+		},
+	}
+
+	s := httptest.NewServer(rec)
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	if _, err := c.RemoveSnapshots("document1"); err == nil {
+		t.Errorf("expected error for missing snapshot IDs")
+	}
+
+	removed, err := c.RemoveSnapshots("document1", "s1", "s2")
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if !reflect.DeepEqual(removed, []string{"s1", "s2"}) {
+		t.Errorf("unexpected removed snapshots %v", removed)
+	}
+
+	if _, err := c.RemoveSelectedSnapshots("document1", PastSnapshots); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	rec.check(t,
+		`POST /api/docs/document1/snapshots/remove {"snapshotIds":["s1","s2"]}`,
+		`POST /api/docs/document1/snapshots/remove {"select":"past"}`,
+	)
+
+	if _, err := c.ForkSnapshot(Snapshot{SnapshotID: "s1"}); err == nil {
+		t.Errorf("expected error for snapshot without document ID")
+	}
+}
+
+func TestDocStateRequests(t *testing.T) {
+	cmp := `{"left":{"n":3,"h":"aaa"},"right":{"n":4,"h":"bbb"},"parent":{"n":3,"h":"aaa"},"summary":"right"}`
+
+	rec := &callRecorder{
+		responses: map[string]string{
+			"GET /api/docs/document1/states":            `{"states":[{"n":4,"h":"bbb"},{"n":3,"h":"aaa"}]}`,
+			"GET /api/docs/document1/compare/document2": cmp,
+			"GET /api/docs/document1/compare":           cmp,
+		},
+	}
+
+	s := httptest.NewServer(rec)
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	states, err := c.GetDocStates("document1")
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if !reflect.DeepEqual(states, []DocState{{N: 4, H: "bbb"}, {N: 3, H: "aaa"}}) {
+		t.Errorf("unexpected states %v", states)
+	}
+
+	result, err := c.CompareDocuments("document1", "document2", true)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if result.Summary != CompareRight {
+		t.Errorf("unexpected comparison %#v", result)
+	}
+
+	if _, err := c.CompareDocuments("document1", "document2", false); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if _, err := c.CompareVersions("document1", "aaa", ""); err == nil {
+		t.Errorf("expected error for missing right state hash")
+	}
+
+	if _, err := c.CompareVersions("document1", "aaa", "bbb"); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	rec.check(t,
+		"GET /api/docs/document1/states",
+		"GET /api/docs/document1/compare/document2?detail=1",
+		"GET /api/docs/document1/compare/document2",
+		"GET /api/docs/document1/compare?left=aaa&right=bbb",
+	)
+}
+
+func TestDocStateComparison(t *testing.T) {
+	data := []byte(`{
+		"left": {"n": 3, "h": "aaa"},
+		"right": {"n": 4, "h": "bbb"},
+		"parent": {"n": 2, "h": "ccc"},
+		"summary": "both",
+		"details": {
+			"leftChanges": {"tableRenames": [], "tableDeltas": {}},
+			"rightChanges": {
+				"tableRenames": [[null, "Claims"]],
+				"tableDeltas": {
+					"Claims": {
+						"addRows": [1],
+						"updateRows": [2, 3],
+						"removeRows": [],
+						"columnRenames": [],
+						"columnDeltas": {"Name": {"1": [null, ["new"]], "2": [["old"], ["changed"]], "3": ["?", ["x"]]}}
+					}
+				}
+			}
+		}
+	}`)
+
+	var cmp DocStateComparison
+	if err := json.Unmarshal(data, &cmp); err != nil {
+		t.Fatalf("error unmarshaling: %v", err)
+	}
+
+	if cmp.Summary != CompareBoth || cmp.Parent == nil || cmp.Parent.H != "ccc" {
+		t.Errorf("unexpected comparison %#v", cmp)
+	}
+
+	right := cmp.Details.RightChanges
+	if right.TableRenames[0] != [2]string{"", "Claims"} {
+		t.Errorf("unexpected table renames %v", right.TableRenames)
+	}
+
+	names := right.TableDeltas["Claims"].ColumnDeltas["Name"]
+	if names[1].Before != nil || string(names[1].After) != `"new"` || string(names[2].Before) != `"old"` {
+		t.Errorf("unexpected cell deltas %#v", names)
+	}
+
+	if !names[3].BeforeUnknown || names[3].Before != nil || names[3].AfterUnknown || string(names[3].After) != `"x"` {
+		t.Errorf("expected unknown before value but got %#v", names[3])
+	}
+}
+
+func TestApplyUserActions(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		expected := `[["AddRecord","Claims",null,{"name":"one","open":true}],["RenameColumn","Claims","name","title"],["BulkRemoveRecord","Claims",[1,2]]]`
+		if string(data) != expected {
+			http.Error(w, string(data), http.StatusBadRequest)
+			return
+		}
+
+		w.Write([]byte(`{"actionNum":12,"actionHash":"abc","retValues":[3,null,null],"isModification":true}`))
+	}))
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	res, err := c.ApplyUserActions("document1",
+		AddRecordAction("Claims", typedFields{Name: "one", Open: true}),
+		RenameColumnAction("Claims", "name", "title"),
+		BulkRemoveRecordAction("Claims", []int{1, 2}),
+	)
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if res.ActionNum != 12 || len(res.RetValues) != 3 || string(res.RetValues[0]) != "3" {
+		t.Errorf("unexpected result %#v", res)
+	}
+}
+
+func TestBulkRecordActions(t *testing.T) {
+	action, err := BulkAddRecordAction("Claims", ColumnValues{"a": {1, 2, 3}, "b": {"x", "y", "z"}})
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if ids := action[2].([]interface{}); len(ids) != 3 {
+		t.Errorf("expected 3 row IDs but got %d", len(ids))
+	}
+
+	if _, err := BulkAddRecordAction("Claims", ColumnValues{"a": {1, 2, 3}, "b": {1}}); err == nil {
+		t.Errorf("expected error for columns with different lengths")
+	}
+
+	if _, err := BulkUpdateRecordAction("Claims", []int{1, 2}, ColumnValues{"a": {1, 2}}); err != nil {
+		t.Errorf("expected no errors but got %v", err)
+	}
+
+	if _, err := BulkUpdateRecordAction("Claims", []int{1, 2}, ColumnValues{"a": {1, 2}, "b": {1}}); err == nil {
+		t.Errorf("expected error for column without a value for each row ID")
+	}
+}
+
+func TestTableLifecycle(t *testing.T) {
+	rec := &callRecorder{
+		responses: map[string]string{
+			"POST /api/docs/document1/apply": `{"actionNum":1,"retValues":[null]}`,
+		},
+	}
+
+	s := httptest.NewServer(rec)
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	if _, err := c.PatchTables("document1", Table{ID: "Claims", Fields: TableFields{TableRef: 2, OnDemand: true}}); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if err := c.RenameTable("document1", "Claims", "Claim"); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if err := c.DeleteTable("document1", "Claim"); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	rec.check(t,
+		`PATCH /api/docs/document1/tables {"tables":[{"id":"Claims","fields":{"onDemand":true}}]}`,
+		`POST /api/docs/document1/apply [["RenameTable","Claims","Claim"]]`,
+		`POST /api/docs/document1/apply [["RemoveTable","Claim"]]`,
+	)
+}
+
+func TestUpsertColumns(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/docs/document1/tables/Claims/columns" {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		q := r.URL.Query()
+		if q.Get("noadd") != "true" || q.Get("replaceall") != "true" || q.Has("noupdate") {
+			http.Error(w, r.URL.RawQuery, http.StatusBadRequest)
+			return
+		}
+
+		var cols Columns
+		if err := json.NewDecoder(r.Body).Decode(&cols); err != nil || len(cols.Columns) != 1 || cols.Columns[0].ID != "Name" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		w.Write([]byte(`{"columns":[{"id":"Name"}]}`))
+	}))
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	opts := ColumnUpsertOptions{NoAdd: true, ReplaceAll: true}
+	col := Column{ID: "Name", Fields: ColumnField{Label: "Name", Type: TextField}}
+
+	if _, err := c.UpsertColumns("document1", Table{ID: "Claims"}, opts, col); err != nil {
+		t.Errorf("expected no errors but got %v", err)
+	}
+}
+
+func TestDeleteColumn(t *testing.T) {
+	rec := &callRecorder{}
+
+	s := httptest.NewServer(rec)
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	if _, err := c.DeleteColumn("document1", Table{ID: "Claims"}, "Old"); err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if _, err := c.DeleteColumn("document1", Table{ID: "Claims"}, ""); err == nil {
+		t.Errorf("expected error for missing column ID")
+	}
+
+	rec.check(t, "DELETE /api/docs/document1/tables/Claims/columns/Old")
+}
+
+func TestDiffSchema(t *testing.T) {
+	current := []Table{
+		{
+			ID: "Claims",
+			Columns: []Column{
+				{ID: "Name", Fields: ColumnField{Label: "Name", Type: TextField}},
+				{ID: "Amount", Fields: ColumnField{Label: "Amount", Type: TextField}},
+				{ID: "Old", Fields: ColumnField{Label: "Old", Type: AnyField}},
+			},
+		},
+	}
+
+	desired := []Table{
+		{
+			ID: "Claims",
+			Columns: []Column{
+				{ID: "Name", Fields: ColumnField{Type: TextField}},
+				{ID: "Amount", Fields: ColumnField{Type: NumericField}},
+				{ID: "Open", Fields: ColumnField{Type: BoolField}},
+			},
+		},
+		{
+			ID:      "Regions",
+			Columns: []Column{{ID: "Code", Fields: ColumnField{Type: TextField}}},
+		},
+	}
+
+	plan := MigrationPlan{
+		Document: "document1",
+		Steps:    diffSchema(current, desired, MigrateOptions{RemoveColumns: true}),
+	}
+
+	expected := "~ column Claims.Amount: type: Text -> Numeric\n" +
+		"+ column Claims.Open (Bool)\n" +
+		"- column Claims.Old\n" +
+		"+ table Regions (Code)\n"
+
+	if plan.String() != expected {
+		t.Errorf("expected \n%s\nbut got \n%s", expected, plan)
+	}
+
+	if label := plan.Steps[0].Columns[0].Fields.Label; label != "Amount" {
+		t.Errorf("expected altered column to keep its label but got %q", label)
+	}
+
+	if steps := diffSchema(current, desired[:1], MigrateOptions{}); len(steps) != 2 {
+		t.Errorf("expected columns to be kept without RemoveColumns but got %v", steps)
+	}
+}
+
+func TestApplyMigration(t *testing.T) {
+	current := []Table{
+		{
+			ID: "Claims",
+			Columns: []Column{
+				{ID: "Total", Fields: ColumnField{Label: "Total", Type: NumericField, IsFormula: true, Formula: "$A + $B"}},
+				{ID: "Tax", Fields: ColumnField{Label: "Tax", Type: NumericField, IsFormula: true, Formula: "$Total * 0.2"}},
+				{ID: "Code", Fields: ColumnField{Label: "Code", Type: TextField, RecalcWhen: NewAndUpdate}},
+			},
+		},
+	}
+
+	desired := []Table{
+		{
+			ID: "Claims",
+			Columns: []Column{
+				{ID: "Total", Fields: ColumnField{Type: NumericField}},
+				{ID: "Tax", Fields: ColumnField{Type: NumericField, IsFormula: true, Formula: "$Total * 0.1"}},
+				{ID: "Code", Fields: ColumnField{Type: IntField}},
+				{ID: "Open", Fields: ColumnField{Type: BoolField}},
+			},
+		},
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/docs/document1/tables/Claims/columns" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		var body struct {
+			Columns []struct {
+				ID     string                 `json:"id"`
+				Fields map[string]interface{} `json:"fields"`
+			} `json:"columns"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		expected := []map[string]interface{}{
+			{"formula": "$Total * 0.1"},
+			{"type": "Int"},
+			{"label": "Open", "type": "Bool", "recalcWhen": float64(0)},
+		}
+
+		if len(body.Columns) != len(expected) {
+			http.Error(w, fmt.Sprintf("unexpected columns %v", body.Columns), http.StatusBadRequest)
+			return
+		}
+
+		for i, v := range body.Columns {
+			if !reflect.DeepEqual(v.Fields, expected[i]) {
+				http.Error(w, fmt.Sprintf("unexpected fields for %s: %v", v.ID, v.Fields), http.StatusBadRequest)
+				return
+			}
+		}
+
+		w.Write([]byte(`{"columns":[]}`))
+	}))
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	plan := MigrationPlan{
+		Document: "document1",
+		Steps:    diffSchema(current, desired, MigrateOptions{}),
+	}
+
+	if err := c.ApplyMigration(plan); err != nil {
+		t.Errorf("expected no errors but got %v", err)
+	}
+
+	cleared := diffSchema(current, desired[:1], MigrateOptions{ClearFormulas: true})
+	if len(cleared) != 4 || !reflect.DeepEqual(cleared[0].Fields, map[string]interface{}{"isFormula": false, "formula": ""}) {
+		t.Errorf("expected formula of Total to be cleared but got %v", cleared)
+	}
+}
+
+func TestTypedSchema(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/docs/document1/tables":
+			w.Write([]byte(`{"tables":[{"id":"Claims","fields":{"tableRef":1,"onDemand":false,"primaryViewId":1}}]}`))
+		case "/api/docs/document1/tables/Claims/columns":
+			w.Write([]byte(`{"columns":[
+				{"id":"Opened","fields":{"label":"Opened","type":"DateTime:America/New_York","widgetOptions":"{\"dateFormat\":\"YYYY-MM-DD\",\"timeFormat\":\"h:mma\",\"rulesOptions\":[]}","recalcWhen":0,"recalcDeps":null}},
+				{"id":"Customer","fields":{"label":"Customer","type":"Ref:Customers","widgetOptions":"","recalcWhen":2,"recalcDeps":["L",3,4],"visibleCol":7}}
+			]}`))
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	c := NewClient(SetURL(s.URL))
+
+	tables, err := c.ListTablesTyped("document1")
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if len(tables) != 1 || tables[0].ID != "Claims" || tables[0].Fields.TableRef != 1 {
+		t.Fatalf("unexpected tables %#v", tables)
+	}
+
+	cols, err := c.GetColumnsTyped("document1", tables[0])
+	if err != nil {
+		t.Fatalf("expected no errors but got %v", err)
+	}
+
+	if len(cols) != 2 || !reflect.DeepEqual(cols[1].Fields.RecalcDeps, []int{3, 4}) || cols[1].Fields.RecalcWhen != NewAndUpdate {
+		t.Fatalf("unexpected columns %#v", cols)
+	}
+
+	if info := cols[0].Fields.Type.Parse(); info != (FieldTypeInfo{Base: DateTimeField, Timezone: "America/New_York"}) {
+		t.Errorf("unexpected type info %#v", info)
+	}
+
+	if info := cols[1].Fields.Type.Parse(); info != (FieldTypeInfo{Base: RefField, RefTable: "Customers"}) {
+		t.Errorf("unexpected type info %#v", info)
+	}
+
+	opts, err := cols[0].Fields.ParseWidgetOptions()
+	if err != nil {
+		t.Fatalf("error parsing widget options: %v", err)
+	}
+
+	if opts.DateFormat != "YYYY-MM-DD" || opts.TimeFormat != "h:mma" || string(opts.Extra["rulesOptions"]) != "[]" {
+		t.Errorf("unexpected widget options %#v", opts)
+	}
+
+	var f ColumnField
+	if err := f.SetWidgetOptions(opts); err != nil {
+		t.Fatalf("error setting widget options: %v", err)
+	}
+
+	if !jsonEqual(f.WidgetOptions, cols[0].Fields.WidgetOptions) {
+		t.Errorf("expected widget options to round trip but got %s", f.WidgetOptions)
+	}
+}
@@ -0,0 +1,372 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type MigrationStepKind string
+
+const (
+	AddTableStep     MigrationStepKind = "add table"
+	AddColumnStep    MigrationStepKind = "add column"
+	AlterColumnStep  MigrationStepKind = "alter column"
+	RemoveColumnStep MigrationStepKind = "remove column"
+)
+
+// MigrationStep is a single change needed to make a document match the desired schema
+type MigrationStep struct {
+	Kind  MigrationStepKind
+	Table TableID
+	// The added table, or the table holding the added or altered column
+	Columns []Column
+	// ID of the removed column
+	Column string
+	// Human readable description of altered fields, e.g. "type: Text -> Int"
+	Changes []string
+	// Altered fields of the column as sent to Grist. Only changed fields are
+	// included, so cleared values such as an empty formula are sent explicitly
+	Fields map[string]interface{}
+}
+
+// migrationColumn is a column in an upsert, whose fields are either a full
+// *ColumnField for added columns or the changed fields of altered columns
+type migrationColumn struct {
+	ID     string      `json:"id"`
+	Fields interface{} `json:"fields"`
+}
+
+func (s MigrationStep) String() string {
+	switch s.Kind {
+	case AddTableStep:
+		ids := make([]string, 0, len(s.Columns))
+		for _, v := range s.Columns {
+			ids = append(ids, v.ID)
+		}
+		return fmt.Sprintf("+ table %s (%s)", s.Table, strings.Join(ids, ", "))
+	case AddColumnStep:
+		return fmt.Sprintf("+ column %s.%s (%s)", s.Table, s.Columns[0].ID, s.Columns[0].Fields.Type)
+	case AlterColumnStep:
+		return fmt.Sprintf("~ column %s.%s: %s", s.Table, s.Columns[0].ID, strings.Join(s.Changes, ", "))
+	case RemoveColumnStep:
+		return fmt.Sprintf("- column %s.%s", s.Table, s.Column)
+	default:
+		return string(s.Kind)
+	}
+}
+
+// MigrationPlan lists the steps to migrate a document, in the order they are applied
+type MigrationPlan struct {
+	Document DocumentID
+	Steps    []MigrationStep
+}
+
+// String returns the plan with one step per line, for dry runs
+func (p MigrationPlan) String() string {
+	if len(p.Steps) == 0 {
+		return fmt.Sprintf("document %s is up to date\n", p.Document)
+	}
+
+	var b strings.Builder
+	for _, v := range p.Steps {
+		b.WriteString(v.String())
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+type MigrateOptions struct {
+	// Compute the plan without applying it
+	DryRun bool
+	// Remove columns missing from the desired tables. Columns are kept by default since removing them loses data
+	RemoveColumns bool
+	// Compare formulas even when the desired column has none, which turns formula
+	// columns into data columns and clears trigger formulas. Formulas are only
+	// changed when the desired column sets one by default
+	ClearFormulas bool
+}
+
+// Migrate changes the tables of a document to match the desired tables and
+// returns the plan that was applied. Tables not listed in desired are left untouched.
+func (c *Client) Migrate(document DocumentID, desired []Table, opts MigrateOptions) (MigrationPlan, error) {
+	return c.MigrateContext(context.Background(), document, desired, opts)
+}
+
+// MigrateContext changes the tables of a document to match the desired tables using the provided context
+func (c *Client) MigrateContext(ctx context.Context, document DocumentID, desired []Table, opts MigrateOptions) (MigrationPlan, error) {
+	plan, err := c.PlanMigrationContext(ctx, document, desired, opts)
+	if err != nil {
+		return plan, err
+	}
+
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	return plan, c.ApplyMigrationContext(ctx, plan)
+}
+
+// PlanMigration computes the steps needed to make the document match the desired tables without applying them
+func (c *Client) PlanMigration(document DocumentID, desired []Table, opts MigrateOptions) (MigrationPlan, error) {
+	return c.PlanMigrationContext(context.Background(), document, desired, opts)
+}
+
+// PlanMigrationContext computes the steps needed to migrate the document using the provided context
+func (c *Client) PlanMigrationContext(ctx context.Context, document DocumentID, desired []Table, opts MigrateOptions) (MigrationPlan, error) {
+	current, err := c.getSchema(ctx, document)
+	if err != nil {
+		return MigrationPlan{}, err
+	}
+
+	return MigrationPlan{
+		Document: document,
+		Steps:    diffSchema(current, desired, opts),
+	}, nil
+}
+
+// ApplyMigration applies the steps of a plan. New tables are created first,
+// then columns are added or updated with a single upsert per table and finally
+// removed columns are deleted.
+func (c *Client) ApplyMigration(plan MigrationPlan) error {
+	return c.ApplyMigrationContext(context.Background(), plan)
+}
+
+// ApplyMigrationContext applies the steps of a plan using the provided context
+func (c *Client) ApplyMigrationContext(ctx context.Context, plan MigrationPlan) error {
+	var tables []Table
+	upserts := map[TableID][]migrationColumn{}
+	var order []TableID
+	var removals []MigrationStep
+
+	for _, v := range plan.Steps {
+		switch v.Kind {
+		case AddTableStep:
+			tables = append(tables, Table{ID: v.Table, Columns: v.Columns})
+		case AddColumnStep, AlterColumnStep:
+			if _, ok := upserts[v.Table]; !ok {
+				order = append(order, v.Table)
+			}
+
+			for _, col := range v.Columns {
+				col := col
+				var fields interface{} = &col.Fields
+				if v.Kind == AlterColumnStep {
+					fields = v.Fields
+				}
+
+				upserts[v.Table] = append(upserts[v.Table], migrationColumn{ID: col.ID, Fields: fields})
+			}
+		case RemoveColumnStep:
+			removals = append(removals, v)
+		default:
+			return fmt.Errorf("unknown migration step %q", v.Kind)
+		}
+	}
+
+	if len(tables) > 0 {
+		if _, err := c.CreateTablesContext(ctx, plan.Document, tables...); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range order {
+		body := struct {
+			Columns []migrationColumn `json:"columns"`
+		}{
+			Columns: upserts[v],
+		}
+
+		if _, err := c.sendColumns(ctx, http.MethodPut, plan.Document, Table{ID: v}, nil, body); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range removals {
+		if _, err := c.DeleteColumnContext(ctx, plan.Document, Table{ID: v.Table}, v.Column); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getSchema fetches every table of the document along with its columns
+func (c *Client) getSchema(ctx context.Context, document DocumentID) ([]Table, error) {
+	tables, err := c.ListTablesTypedContext(ctx, document)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range tables {
+		cols, err := c.GetColumnsTypedContext(ctx, document, v)
+		if err != nil {
+			return nil, err
+		}
+
+		tables[i].Columns = cols
+	}
+
+	return tables, nil
+}
+
+// diffSchema computes the steps to turn current into desired, following the order of desired
+func diffSchema(current, desired []Table, opts MigrateOptions) []MigrationStep {
+	existing := make(map[TableID]Table, len(current))
+	for _, v := range current {
+		existing[v.ID] = v
+	}
+
+	var steps []MigrationStep
+
+	for _, table := range desired {
+		cur, ok := existing[table.ID]
+		if !ok {
+			cols := make([]Column, 0, len(table.Columns))
+			for _, v := range table.Columns {
+				cols = append(cols, newMigrationColumn(v))
+			}
+
+			steps = append(steps, MigrationStep{
+				Kind:    AddTableStep,
+				Table:   table.ID,
+				Columns: cols,
+			})
+			continue
+		}
+
+		curCols := make(map[string]Column, len(cur.Columns))
+		for _, v := range cur.Columns {
+			curCols[v.ID] = v
+		}
+
+		wanted := make(map[string]bool, len(table.Columns))
+		for _, col := range table.Columns {
+			wanted[col.ID] = true
+
+			curCol, ok := curCols[col.ID]
+			if !ok {
+				steps = append(steps, MigrationStep{
+					Kind:    AddColumnStep,
+					Table:   table.ID,
+					Columns: []Column{newMigrationColumn(col)},
+				})
+				continue
+			}
+
+			changes, fields := columnChanges(curCol.Fields, col.Fields, opts.ClearFormulas)
+			if len(changes) == 0 {
+				continue
+			}
+
+			altered := col
+			// report the current label rather than an empty one, it isn't sent unless it changed
+			if altered.Fields.Label == "" {
+				altered.Fields.Label = curCol.Fields.Label
+			}
+
+			steps = append(steps, MigrationStep{
+				Kind:    AlterColumnStep,
+				Table:   table.ID,
+				Columns: []Column{altered},
+				Changes: changes,
+				Fields:  fields,
+			})
+		}
+
+		if !opts.RemoveColumns {
+			continue
+		}
+
+		for _, v := range cur.Columns {
+			if wanted[v.ID] {
+				continue
+			}
+
+			steps = append(steps, MigrationStep{
+				Kind:   RemoveColumnStep,
+				Table:  table.ID,
+				Column: v.ID,
+			})
+		}
+	}
+
+	return steps
+}
+
+// newMigrationColumn defaults the label of a new column to its ID
+func newMigrationColumn(col Column) Column {
+	if col.Fields.Label == "" {
+		col.Fields.Label = col.ID
+	}
+
+	return col
+}
+
+// columnChanges describes the fields of desired that differ from current and
+// returns them as the fields to send. Empty labels and widget options in
+// desired are ignored, as are empty formulas unless clearFormulas is set.
+func columnChanges(current, desired ColumnField, clearFormulas bool) ([]string, map[string]interface{}) {
+	var changes []string
+	fields := map[string]interface{}{}
+
+	if desired.Type != "" && current.Type != desired.Type {
+		changes = append(changes, fmt.Sprintf("type: %s -> %s", current.Type, desired.Type))
+		fields["type"] = desired.Type
+	}
+
+	formulaSet := clearFormulas || desired.IsFormula || desired.Formula != ""
+
+	if formulaSet && current.IsFormula != desired.IsFormula {
+		changes = append(changes, fmt.Sprintf("isFormula: %t -> %t", current.IsFormula, desired.IsFormula))
+		fields["isFormula"] = desired.IsFormula
+	}
+
+	if formulaSet && current.Formula != desired.Formula {
+		changes = append(changes, fmt.Sprintf("formula: %q -> %q", current.Formula, desired.Formula))
+		fields["formula"] = desired.Formula
+	}
+
+	if desired.Label != "" && current.Label != desired.Label {
+		changes = append(changes, fmt.Sprintf("label: %q -> %q", current.Label, desired.Label))
+		fields["label"] = desired.Label
+	}
+
+	if desired.WidgetOptions != "" && !jsonEqual(current.WidgetOptions, desired.WidgetOptions) {
+		changes = append(changes, fmt.Sprintf("widgetOptions: %s -> %s", current.WidgetOptions, desired.WidgetOptions))
+		fields["widgetOptions"] = desired.WidgetOptions
+	}
+
+	return changes, fields
+}
+
+// jsonEqual compares two JSON documents ignoring formatting and key order
+func jsonEqual(a, b string) bool {
+	var x, y interface{}
+	if json.Unmarshal([]byte(a), &x) != nil || json.Unmarshal([]byte(b), &y) != nil {
+		return a == b
+	}
+
+	ax, _ := json.Marshal(x)
+	by, _ := json.Marshal(y)
+
+	return bytes.Equal(ax, by)
+}
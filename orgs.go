@@ -15,11 +15,16 @@
 package gorist
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 )
 
+// CurrentOrg can be used in place of an organization ID or domain to refer to the organization of the request URL
+const CurrentOrg = "current"
+
 type Org struct {
 	Name               string         `json:"name,omitempty"`
 	CreatedAt          string         `json:"createdAt,omitempty"`
@@ -48,32 +53,152 @@ type Options struct {
 	Locale string `json:"locale,omitempty"`
 }
 
+// OrgUpdate holds the new name and domain of an organization. Empty fields are left unchanged
+type OrgUpdate struct {
+	Name   string `json:"name,omitempty"`
+	Domain string `json:"domain,omitempty"`
+}
+
 // ListOrgs gets all organizations associated with the current token
 func (c *Client) ListOrgs() (json.RawMessage, error) {
+	return c.ListOrgsContext(context.Background())
+}
+
+// ListOrgsContext gets all organizations associated with the current token using the provided context
+func (c *Client) ListOrgsContext(ctx context.Context) (json.RawMessage, error) {
 	request := GristRequest{
 		Path:   "/api/orgs",
 		Method: http.MethodGet,
 	}
 
-	return c.httpRequest(request)
+	return c.httpRequest(ctx, request)
 }
 
-// GetOrg gets the details of an organization based on the ID
+// GetOrg gets the details of an organization based on the ID. The id can also be the domain or CurrentOrg
 func (c *Client) GetOrg(id string) (json.RawMessage, error) {
+	return c.GetOrgContext(context.Background(), id)
+}
+
+// GetOrgContext gets the details of an organization based on the ID using the provided context
+func (c *Client) GetOrgContext(ctx context.Context, id string) (json.RawMessage, error) {
 	request := GristRequest{
 		Path:   fmt.Sprintf("/api/orgs/%s", id),
 		Method: http.MethodGet,
 	}
 
-	return c.httpRequest(request)
+	return c.httpRequest(ctx, request)
 }
 
 // GetOrgWorkspacesAndDocuments retrieves all workspaces and documents in the workspaces for an organization
 func (c *Client) GetOrgWorkspacesAndDocuments(id string) (json.RawMessage, error) {
+	return c.GetOrgWorkspacesAndDocumentsContext(context.Background(), id)
+}
+
+// GetOrgWorkspacesAndDocumentsContext retrieves all workspaces and documents for an organization using the provided context
+func (c *Client) GetOrgWorkspacesAndDocumentsContext(ctx context.Context, id string) (json.RawMessage, error) {
 	request := GristRequest{
 		Path:   fmt.Sprintf("/api/orgs/%s/workspaces", id),
 		Method: http.MethodGet,
 	}
 
-	return c.httpRequest(request)
+	return c.httpRequest(ctx, request)
+}
+
+// ListOrgsTyped gets all organizations associated with the current token
+func (c *Client) ListOrgsTyped() ([]Org, error) {
+	return c.ListOrgsTypedContext(context.Background())
+}
+
+// ListOrgsTypedContext gets all organizations associated with the current token using the provided context
+func (c *Client) ListOrgsTypedContext(ctx context.Context) ([]Org, error) {
+	resp, err := c.ListOrgsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var orgs []Org
+	if err := json.Unmarshal(resp, &orgs); err != nil {
+		return nil, err
+	}
+
+	return orgs, nil
+}
+
+// GetOrgTyped gets the details of an organization. The id can be the numeric ID, the domain or CurrentOrg
+func (c *Client) GetOrgTyped(id string) (Org, error) {
+	return c.GetOrgTypedContext(context.Background(), id)
+}
+
+// GetOrgTypedContext gets the details of an organization using the provided context
+func (c *Client) GetOrgTypedContext(ctx context.Context, id string) (Org, error) {
+	var org Org
+
+	resp, err := c.GetOrgContext(ctx, id)
+	if err != nil {
+		return org, err
+	}
+
+	err = json.Unmarshal(resp, &org)
+
+	return org, err
+}
+
+// GetOrgWorkspacesAndDocumentsTyped retrieves all workspaces and documents in the workspaces for an organization
+func (c *Client) GetOrgWorkspacesAndDocumentsTyped(id string) ([]Workspace, error) {
+	return c.GetOrgWorkspacesAndDocumentsTypedContext(context.Background(), id)
+}
+
+// GetOrgWorkspacesAndDocumentsTypedContext retrieves all workspaces and documents for an organization using the provided context
+func (c *Client) GetOrgWorkspacesAndDocumentsTypedContext(ctx context.Context, id string) ([]Workspace, error) {
+	resp, err := c.GetOrgWorkspacesAndDocumentsContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var workspaces []Workspace
+	if err := json.Unmarshal(resp, &workspaces); err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+// UpdateOrg renames an organization or changes its domain
+func (c *Client) UpdateOrg(id string, update OrgUpdate) error {
+	return c.UpdateOrgContext(context.Background(), id, update)
+}
+
+// UpdateOrgContext renames an organization or changes its domain using the provided context
+func (c *Client) UpdateOrgContext(ctx context.Context, id string, update OrgUpdate) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/orgs/%s", id),
+		Method: http.MethodPatch,
+		Data:   bytes.NewReader(data),
+	}
+
+	_, err = c.httpRequest(ctx, request)
+
+	return err
+}
+
+// DeleteOrg permanently deletes an organization along with its workspaces and documents
+func (c *Client) DeleteOrg(id string) error {
+	return c.DeleteOrgContext(context.Background(), id)
+}
+
+// DeleteOrgContext permanently deletes an organization using the provided context
+func (c *Client) DeleteOrgContext(ctx context.Context, id string) error {
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/orgs/%s", id),
+		Method: http.MethodDelete,
+	}
+
+	_, err := c.httpRequest(ctx, request)
+
+	return err
 }
@@ -0,0 +1,188 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// WebhookSecretHeader is the header checked for the shared secret
+const WebhookSecretHeader = "X-Webhook-Secret"
+
+// WebhookSecretParam is the query parameter checked for the shared secret
+const WebhookSecretParam = "secret"
+
+type WebhookHandlerOpt func(*WebhookHandler)
+
+// WebhookHandler receives Grist webhook deliveries and dispatches the changed
+// records to the callback registered for the table. Any error response makes
+// Grist retry the delivery later.
+type WebhookHandler struct {
+	// Shared secret expected in the X-Webhook-Secret header or the secret query parameter
+	Secret string
+	// Determines the table of a delivery. Defaults to the last element of the URL path
+	TableFromRequest func(*http.Request) TableID
+
+	mu       sync.RWMutex
+	handlers map[TableID]func(context.Context, []json.RawMessage) error
+}
+
+func NewWebhookHandler(opts ...WebhookHandlerOpt) *WebhookHandler {
+	h := &WebhookHandler{
+		handlers: map[TableID]func(context.Context, []json.RawMessage) error{},
+	}
+
+	for _, v := range opts {
+		v(h)
+	}
+
+	if h.TableFromRequest == nil {
+		h.TableFromRequest = func(r *http.Request) TableID {
+			return TableID(path.Base(strings.TrimSuffix(r.URL.Path, "/")))
+		}
+	}
+
+	return h
+}
+
+// SetWebhookSecret requires deliveries to carry the shared secret
+func SetWebhookSecret(secret string) WebhookHandlerOpt {
+	return func(h *WebhookHandler) {
+		h.Secret = secret
+	}
+}
+
+// SetWebhookTableResolver overrides how the table of a delivery is determined
+func SetWebhookTableResolver(fn func(*http.Request) TableID) WebhookHandlerOpt {
+	return func(h *WebhookHandler) {
+		h.TableFromRequest = fn
+	}
+}
+
+// HandleTable registers the callback for deliveries of the table. Each
+// delivered record is decoded into a Record with its fields in T.
+func HandleTable[T any](h *WebhookHandler, table TableID, fn func(context.Context, []Record[T]) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.handlers[table] = func(ctx context.Context, payload []json.RawMessage) error {
+		records, err := decodeWebhookRecords[T](payload)
+		if err != nil {
+			return webhookDecodeError{err}
+		}
+
+		return fn(ctx, records)
+	}
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.authorized(r) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	h.mu.RLock()
+	fn, ok := h.handlers[h.TableFromRequest(r)]
+	h.mu.RUnlock()
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	var payload []json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := fn(r.Context(), payload); err != nil {
+		if _, ok := err.(webhookDecodeError); ok {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) authorized(r *http.Request) bool {
+	if h.Secret == "" {
+		return true
+	}
+
+	secret := r.Header.Get(WebhookSecretHeader)
+	if secret == "" {
+		secret = r.URL.Query().Get(WebhookSecretParam)
+	}
+
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(h.Secret)) == 1
+}
+
+type webhookDecodeError struct {
+	err error
+}
+
+func (e webhookDecodeError) Error() string {
+	return e.err.Error()
+}
+
+// decodeWebhookRecords decodes the flat {"id": 1, "col": value} objects Grist
+// delivers into records
+func decodeWebhookRecords[T any](payload []json.RawMessage) ([]Record[T], error) {
+	records := make([]Record[T], 0, len(payload))
+
+	for _, v := range payload {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(v, &fields); err != nil {
+			return nil, err
+		}
+
+		id := fields["id"]
+		delete(fields, "id")
+
+		data, err := json.Marshal(map[string]interface{}{
+			"id":     id,
+			"fields": fields,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var r Record[T]
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, err
+		}
+
+		records = append(records, r)
+	}
+
+	return records, nil
+}
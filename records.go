@@ -15,13 +15,174 @@
 package gorist
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
 )
 
+// Record is a single Grist row with its fields decoded into T. Fields are
+// mapped to columns using the standard json struct tags.
+type Record[T any] struct {
+	ID     int `json:"id,omitempty"`
+	Fields T   `json:"fields"`
+}
+
+// Records is the envelope Grist uses when reading and writing rows
+type Records[T any] struct {
+	Records []Record[T] `json:"records"`
+}
+
+// UnmarshalJSON decodes Grist encoded list values into plain slices before
+// decoding the fields into T, so fields like []int work for RefList columns
+func (r *Record[T]) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		ID     int                        `json:"id"`
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	r.ID = raw.ID
+
+	if raw.Fields == nil {
+		return nil
+	}
+
+	flattenFields(reflect.TypeOf((*T)(nil)).Elem(), raw.Fields)
+
+	fields, err := json.Marshal(raw.Fields)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(fields, &r.Fields)
+}
+
+// MarshalJSON encodes plain slice fields as ["L", ...] lists, the reverse of UnmarshalJSON
+func (r Record[T]) MarshalJSON() ([]byte, error) {
+	fields, err := encodeFields(r.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		ID     int             `json:"id,omitempty"`
+		Fields json.RawMessage `json:"fields"`
+	}{
+		ID:     r.ID,
+		Fields: fields,
+	})
+}
+
+// RecordUpdate holds the new field values for an existing row
+type RecordUpdate struct {
+	ID     int         `json:"id"`
+	Fields interface{} `json:"fields"`
+}
+
+// MarshalJSON encodes plain slice fields as ["L", ...] lists
+func (r RecordUpdate) MarshalJSON() ([]byte, error) {
+	fields, err := encodeFields(r.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		ID     int             `json:"id"`
+		Fields json.RawMessage `json:"fields"`
+	}{
+		ID:     r.ID,
+		Fields: fields,
+	})
+}
+
+// RecordUpsert describes a row to add or update. Rows matching all of the
+// Require values are updated with Fields, otherwise a new row is added.
+type RecordUpsert struct {
+	Require interface{} `json:"require"`
+	Fields  interface{} `json:"fields,omitempty"`
+}
+
+// MarshalJSON encodes plain slice values as ["L", ...] lists
+func (r RecordUpsert) MarshalJSON() ([]byte, error) {
+	require, err := encodeFields(r.Require)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields json.RawMessage
+	if r.Fields != nil {
+		if fields, err = encodeFields(r.Fields); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(struct {
+		Require json.RawMessage `json:"require"`
+		Fields  json.RawMessage `json:"fields,omitempty"`
+	}{
+		Require: require,
+		Fields:  fields,
+	})
+}
+
+// OnMany controls which rows are updated when more than one row matches an upsert
+type OnMany string
+
+const (
+	OnManyFirst OnMany = "first"
+	OnManyNone  OnMany = "none"
+	OnManyAll   OnMany = "all"
+)
+
+// UpsertOptions are the query options for UpsertRecords
+type UpsertOptions struct {
+	// Defaults to first when empty
+	OnMany OnMany
+	// Don't add rows when no existing row matches
+	NoAdd bool
+	// Don't update rows when a matching row exists
+	NoUpdate bool
+	// Allow an empty require, which matches every row
+	AllowEmptyRequire bool
+}
+
+func (o UpsertOptions) values() url.Values {
+	q := url.Values{}
+
+	if o.OnMany != "" {
+		q.Set("onmany", string(o.OnMany))
+	}
+
+	if o.NoAdd {
+		q.Set("noadd", "true")
+	}
+
+	if o.NoUpdate {
+		q.Set("noupdate", "true")
+	}
+
+	if o.AllowEmptyRequire {
+		q.Set("allow_empty_require", "true")
+	}
+
+	return q
+}
+
 func (c *Client) GetRecordsWithOptions(opts ...GristRequestOpt) (json.RawMessage, error) {
+	return c.GetRecordsWithOptionsContext(context.Background(), opts...)
+}
+
+// GetRecordsWithOptionsContext gets records described by the request options using the provided context
+func (c *Client) GetRecordsWithOptionsContext(ctx context.Context, opts ...GristRequestOpt) (json.RawMessage, error) {
 	var r GristRequest
 
 	for _, opt := range opts {
@@ -30,20 +191,29 @@ func (c *Client) GetRecordsWithOptions(opts ...GristRequestOpt) (json.RawMessage
 
 	r.Path = fmt.Sprintf("/api/docs/%s/tables/%s/records", r.Document, r.Table)
 
-	return c.getRecords(r)
+	return c.getRecords(ctx, r)
 }
 
 func (c *Client) GetRecords(document DocumentID, table TableID) (json.RawMessage, error) {
+	return c.GetRecordsContext(context.Background(), document, table)
+}
 
+// GetRecordsContext gets all records in a table using the provided context
+func (c *Client) GetRecordsContext(ctx context.Context, document DocumentID, table TableID) (json.RawMessage, error) {
 	request := GristRequest{
 		Path:   fmt.Sprintf("/api/docs/%s/tables/%s/records", document, table),
 		Method: http.MethodGet,
 	}
 
-	return c.getRecords(request)
+	return c.getRecords(ctx, request)
 }
 
 func (c *Client) GetFilteredRecords(document DocumentID, table TableID, filter json.RawMessage) (json.RawMessage, error) {
+	return c.GetFilteredRecordsContext(context.Background(), document, table, filter)
+}
+
+// GetFilteredRecordsContext gets the records in a table matching filter using the provided context
+func (c *Client) GetFilteredRecordsContext(ctx context.Context, document DocumentID, table TableID, filter json.RawMessage) (json.RawMessage, error) {
 	request := GristRequest{
 		Path:   fmt.Sprintf("/api/docs/%s/tables/%s/records", document, table),
 		Method: http.MethodGet,
@@ -53,20 +223,208 @@ func (c *Client) GetFilteredRecords(document DocumentID, table TableID, filter j
 		request.Filter = filter
 	}
 
-	return c.getRecords(request)
+	return c.getRecords(ctx, request)
 
 }
 
-func (c *Client) getRecords(r GristRequest) (json.RawMessage, error) {
-	return c.httpRequest(r)
+func (c *Client) getRecords(ctx context.Context, r GristRequest) (json.RawMessage, error) {
+	return c.httpRequest(ctx, r)
 }
 
 func (c *Client) CreateRecord(document DocumentID, table TableID, r io.Reader) (json.RawMessage, error) {
+	return c.CreateRecordContext(context.Background(), document, table, r)
+}
+
+// CreateRecordContext creates the records read from r using the provided context
+func (c *Client) CreateRecordContext(ctx context.Context, document DocumentID, table TableID, r io.Reader) (json.RawMessage, error) {
 	path := fmt.Sprintf("/api/docs/%s/tables/%s/records", document, table)
 	request := GristRequest{
 		Path:   path,
 		Method: http.MethodPost,
 		Data:   r,
 	}
-	return c.httpRequest(request)
+	return c.httpRequest(ctx, request)
+}
+
+// UpdateRecords modifies the fields of existing rows
+func (c *Client) UpdateRecords(document DocumentID, table TableID, records ...RecordUpdate) error {
+	return c.UpdateRecordsContext(context.Background(), document, table, records...)
+}
+
+// UpdateRecordsContext modifies the fields of existing rows using the provided context
+func (c *Client) UpdateRecordsContext(ctx context.Context, document DocumentID, table TableID, records ...RecordUpdate) error {
+	for _, v := range records {
+		if v.ID == 0 {
+			return errors.New("id required to update record")
+		}
+	}
+
+	data, err := json.Marshal(map[string][]RecordUpdate{"records": records})
+	if err != nil {
+		return err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/tables/%s/records", document, table),
+		Method: http.MethodPatch,
+		Data:   bytes.NewReader(data),
+	}
+
+	_, err = c.httpRequest(ctx, request)
+
+	return err
+}
+
+// UpsertRecords adds or updates rows depending on whether they match the require values of each record
+func (c *Client) UpsertRecords(document DocumentID, table TableID, opts UpsertOptions, records ...RecordUpsert) error {
+	return c.UpsertRecordsContext(context.Background(), document, table, opts, records...)
+}
+
+// UpsertRecordsContext adds or updates rows using the provided context
+func (c *Client) UpsertRecordsContext(ctx context.Context, document DocumentID, table TableID, opts UpsertOptions, records ...RecordUpsert) error {
+	data, err := json.Marshal(map[string][]RecordUpsert{"records": records})
+	if err != nil {
+		return err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/tables/%s/records", document, table),
+		Method: http.MethodPut,
+		Query:  opts.values(),
+		Data:   bytes.NewReader(data),
+	}
+
+	_, err = c.httpRequest(ctx, request)
+
+	return err
+}
+
+// maximum number of row IDs sent in a single delete request
+const deleteChunkSize = 500
+
+// DeleteRecords removes the rows with the given IDs from a table. Large sets of
+// IDs are split across multiple requests.
+func (c *Client) DeleteRecords(document DocumentID, table TableID, ids ...int) error {
+	return c.DeleteRecordsContext(context.Background(), document, table, ids...)
+}
+
+// DeleteRecordsContext removes the rows with the given IDs from a table using the provided context
+func (c *Client) DeleteRecordsContext(ctx context.Context, document DocumentID, table TableID, ids ...int) error {
+	for start := 0; start < len(ids); start += deleteChunkSize {
+		end := start + deleteChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		if err := c.deleteRecords(ctx, document, table, ids[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) deleteRecords(ctx context.Context, document DocumentID, table TableID, ids []int) error {
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/tables/%s/records/delete", document, table),
+		Method: http.MethodPost,
+		Data:   bytes.NewReader(data),
+	}
+
+	_, err = c.httpRequest(ctx, request)
+
+	var gristErr GristError
+	if !errors.As(err, &gristErr) || gristErr.StatusCode != http.StatusNotFound {
+		return err
+	}
+
+	// older Grist servers only support the data endpoint
+	request.Path = fmt.Sprintf("/api/docs/%s/tables/%s/data/delete", document, table)
+	request.Data = bytes.NewReader(data)
+
+	_, err = c.httpRequest(ctx, request)
+
+	return err
+}
+
+// GetRecordsAs gets all records in a table and decodes their fields into T
+func GetRecordsAs[T any](c *Client, document DocumentID, table TableID) ([]Record[T], error) {
+	return GetRecordsAsContext[T](context.Background(), c, document, table)
+}
+
+// GetRecordsAsContext gets all records in a table and decodes their fields into T using the provided context
+func GetRecordsAsContext[T any](ctx context.Context, c *Client, document DocumentID, table TableID) ([]Record[T], error) {
+	data, err := c.GetRecordsContext(ctx, document, table)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRecords[T](data)
+}
+
+// GetRecordsWithOptionsAs gets the records described by the request options and decodes their fields into T
+func GetRecordsWithOptionsAs[T any](c *Client, opts ...GristRequestOpt) ([]Record[T], error) {
+	return GetRecordsWithOptionsAsContext[T](context.Background(), c, opts...)
+}
+
+// GetRecordsWithOptionsAsContext is GetRecordsWithOptionsAs using the provided context
+func GetRecordsWithOptionsAsContext[T any](ctx context.Context, c *Client, opts ...GristRequestOpt) ([]Record[T], error) {
+	data, err := c.GetRecordsWithOptionsContext(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRecords[T](data)
+}
+
+// CreateRecordsFrom encodes each value as the fields of a new record and returns the IDs of the created rows
+func CreateRecordsFrom[T any](c *Client, document DocumentID, table TableID, fields ...T) ([]int, error) {
+	return CreateRecordsFromContext(context.Background(), c, document, table, fields...)
+}
+
+// CreateRecordsFromContext is CreateRecordsFrom using the provided context
+func CreateRecordsFromContext[T any](ctx context.Context, c *Client, document DocumentID, table TableID, fields ...T) ([]int, error) {
+	records := Records[T]{
+		Records: make([]Record[T], 0, len(fields)),
+	}
+
+	for _, v := range fields {
+		records.Records = append(records.Records, Record[T]{Fields: v})
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.CreateRecordContext(ctx, document, table, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := decodeRecords[struct{}](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(created))
+	for _, v := range created {
+		ids = append(ids, v.ID)
+	}
+
+	return ids, nil
+}
+
+func decodeRecords[T any](data json.RawMessage) ([]Record[T], error) {
+	var r Records[T]
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+
+	return r.Records, nil
 }
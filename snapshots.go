@@ -0,0 +1,150 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type Snapshots struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// Snapshot is a point in time copy of a document
+type Snapshot struct {
+	SnapshotID   string    `json:"snapshotId"`
+	LastModified time.Time `json:"lastModified"`
+	// ID that opens the snapshot as a read only document
+	DocID DocumentID `json:"docId"`
+}
+
+// SnapshotSelection selects snapshots to remove without listing their IDs
+type SnapshotSelection string
+
+const (
+	// Every snapshot except the most recent
+	PastSnapshots SnapshotSelection = "past"
+	// Snapshots left in storage that are no longer listed
+	UnlistedSnapshots SnapshotSelection = "unlisted"
+)
+
+type snapshotRemoval struct {
+	SnapshotIDs []string          `json:"snapshotIds,omitempty"`
+	Select      SnapshotSelection `json:"select,omitempty"`
+}
+
+// ListSnapshots gets the snapshots of a document, most recent first
+func (c *Client) ListSnapshots(document DocumentID) ([]Snapshot, error) {
+	return c.ListSnapshotsContext(context.Background(), document)
+}
+
+// ListSnapshotsContext gets the snapshots of a document using the provided context
+func (c *Client) ListSnapshotsContext(ctx context.Context, document DocumentID) ([]Snapshot, error) {
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/snapshots", document),
+		Method: http.MethodGet,
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Snapshots
+	if err := json.Unmarshal(resp, &s); err != nil {
+		return nil, err
+	}
+
+	return s.Snapshots, nil
+}
+
+// RemoveSnapshots removes snapshots of a document and returns the IDs of the removed snapshots
+func (c *Client) RemoveSnapshots(document DocumentID, ids ...string) ([]string, error) {
+	return c.RemoveSnapshotsContext(context.Background(), document, ids...)
+}
+
+// RemoveSnapshotsContext removes snapshots of a document using the provided context
+func (c *Client) RemoveSnapshotsContext(ctx context.Context, document DocumentID, ids ...string) ([]string, error) {
+	if len(ids) < 1 {
+		return nil, errors.New("snapshot IDs required to remove snapshots")
+	}
+
+	return c.removeSnapshots(ctx, document, snapshotRemoval{SnapshotIDs: ids})
+}
+
+// RemoveSelectedSnapshots removes the selected snapshots of a document and returns the IDs of the removed snapshots
+func (c *Client) RemoveSelectedSnapshots(document DocumentID, selection SnapshotSelection) ([]string, error) {
+	return c.RemoveSelectedSnapshotsContext(context.Background(), document, selection)
+}
+
+// RemoveSelectedSnapshotsContext removes the selected snapshots of a document using the provided context
+func (c *Client) RemoveSelectedSnapshotsContext(ctx context.Context, document DocumentID, selection SnapshotSelection) ([]string, error) {
+	return c.removeSnapshots(ctx, document, snapshotRemoval{Select: selection})
+}
+
+func (c *Client) removeSnapshots(ctx context.Context, document DocumentID, removal snapshotRemoval) ([]string, error) {
+	data, err := json.Marshal(removal)
+	if err != nil {
+		return nil, err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/snapshots/remove", document),
+		Method: http.MethodPost,
+		Data:   bytes.NewReader(data),
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed snapshotRemoval
+	if err := json.Unmarshal(resp, &removed); err != nil {
+		return nil, err
+	}
+
+	return removed.SnapshotIDs, nil
+}
+
+// RestoreSnapshot replaces the contents of a document with one of its snapshots
+func (c *Client) RestoreSnapshot(document DocumentID, snapshotID string) error {
+	return c.RestoreSnapshotContext(context.Background(), document, snapshotID)
+}
+
+// RestoreSnapshotContext replaces the contents of a document with one of its snapshots using the provided context
+func (c *Client) RestoreSnapshotContext(ctx context.Context, document DocumentID, snapshotID string) error {
+	return c.ReplaceDocumentContext(ctx, document, DocumentReplace{SnapshotID: snapshotID})
+}
+
+// ForkSnapshot creates a fork of the document as it was at the snapshot
+func (c *Client) ForkSnapshot(snapshot Snapshot) (Fork, error) {
+	return c.ForkSnapshotContext(context.Background(), snapshot)
+}
+
+// ForkSnapshotContext creates a fork of the document as it was at the snapshot using the provided context
+func (c *Client) ForkSnapshotContext(ctx context.Context, snapshot Snapshot) (Fork, error) {
+	if snapshot.DocID == "" {
+		return Fork{}, errors.New("snapshot document ID required to fork snapshot")
+	}
+
+	return c.ForkDocumentContext(ctx, snapshot.DocID)
+}
@@ -0,0 +1,194 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SQLQuery is a read only SELECT statement run against a document
+type SQLQuery struct {
+	SQL string `json:"sql"`
+	// Values for ? placeholders in the statement
+	Args []interface{} `json:"args,omitempty"`
+	// Maximum run time of the query. Grist uses its default when zero
+	Timeout time.Duration `json:"-"`
+}
+
+func (q SQLQuery) MarshalJSON() ([]byte, error) {
+	type QueryAlias SQLQuery
+	t := struct {
+		QueryAlias
+		Timeout int64 `json:"timeout,omitempty"`
+	}{
+		QueryAlias: QueryAlias(q),
+		Timeout:    q.Timeout.Milliseconds(),
+	}
+
+	return json.Marshal(t)
+}
+
+// SQLResult holds the rows returned by a query. Each row holds the values of
+// Columns in the same order.
+type SQLResult struct {
+	// The statement as run by Grist
+	Statement string
+	Columns   []string
+	Rows      [][]json.RawMessage
+}
+
+// QuerySQL runs a read only SELECT statement with optional placeholder arguments
+func (c *Client) QuerySQL(document DocumentID, sql string, args ...interface{}) (*SQLResult, error) {
+	return c.QuerySQLContext(context.Background(), document, sql, args...)
+}
+
+// QuerySQLContext runs a read only SELECT statement using the provided context
+func (c *Client) QuerySQLContext(ctx context.Context, document DocumentID, sql string, args ...interface{}) (*SQLResult, error) {
+	return c.QuerySQLWithOptionsContext(ctx, document, SQLQuery{
+		SQL:  sql,
+		Args: args,
+	})
+}
+
+// QuerySQLWithOptions runs the query described by q
+func (c *Client) QuerySQLWithOptions(document DocumentID, q SQLQuery) (*SQLResult, error) {
+	return c.QuerySQLWithOptionsContext(context.Background(), document, q)
+}
+
+// QuerySQLWithOptionsContext runs the query described by q using the provided context
+func (c *Client) QuerySQLWithOptionsContext(ctx context.Context, document DocumentID, q SQLQuery) (*SQLResult, error) {
+	if q.SQL == "" {
+		return nil, errors.New("sql statement required")
+	}
+
+	data, err := json.Marshal(q)
+	if err != nil {
+		return nil, err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/sql", document),
+		Method: http.MethodPost,
+		Data:   bytes.NewReader(data),
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSQLResult(resp)
+}
+
+// ScanSQL maps each row of the result into T using the json struct tags of T
+func ScanSQL[T any](r *SQLResult) ([]T, error) {
+	out := make([]T, 0, len(r.Rows))
+
+	for _, row := range r.Rows {
+		fields := make(map[string]json.RawMessage, len(row))
+		for i, v := range row {
+			fields[r.Columns[i]] = v
+		}
+
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+
+		var item T
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, err
+		}
+
+		out = append(out, item)
+	}
+
+	return out, nil
+}
+
+// decodeSQLResult decodes the response keeping the column order of the fields
+// objects, which would be lost decoding into maps
+func decodeSQLResult(data json.RawMessage) (*SQLResult, error) {
+	var resp struct {
+		Statement string `json:"statement"`
+		Records   []struct {
+			Fields orderedFields `json:"fields"`
+		} `json:"records"`
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &SQLResult{
+		Statement: resp.Statement,
+		Rows:      make([][]json.RawMessage, 0, len(resp.Records)),
+	}
+
+	for i, v := range resp.Records {
+		if i == 0 {
+			result.Columns = v.Fields.keys
+		}
+
+		if len(v.Fields.keys) != len(result.Columns) {
+			return nil, fmt.Errorf("row %d has %d columns, expected %d", i, len(v.Fields.keys), len(result.Columns))
+		}
+
+		result.Rows = append(result.Rows, v.Fields.values)
+	}
+
+	return result, nil
+}
+
+type orderedFields struct {
+	keys   []string
+	values []json.RawMessage
+}
+
+func (o *orderedFields) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key, ok := t.(string)
+		if !ok {
+			return fmt.Errorf("unexpected field name %v", t)
+		}
+
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+
+		o.keys = append(o.keys, key)
+		o.values = append(o.values, v)
+	}
+
+	return nil
+}
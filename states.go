@@ -0,0 +1,215 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DocState identifies a version of a document by its action number and hash
+type DocState struct {
+	N int    `json:"n"`
+	H string `json:"h"`
+}
+
+type DocStates struct {
+	States []DocState `json:"states"`
+}
+
+// ComparisonSummary describes how two document versions relate
+type ComparisonSummary string
+
+const (
+	// Both versions are identical
+	CompareSame ComparisonSummary = "same"
+	// Only the left version has changes since the common parent
+	CompareLeft ComparisonSummary = "left"
+	// Only the right version has changes since the common parent
+	CompareRight ComparisonSummary = "right"
+	// Both versions have changes since the common parent
+	CompareBoth ComparisonSummary = "both"
+	// The versions share no history
+	CompareUnrelated ComparisonSummary = "unrelated"
+)
+
+// DocStateComparison is the result of comparing two document versions
+type DocStateComparison struct {
+	Left  DocState `json:"left"`
+	Right DocState `json:"right"`
+	// Most recent common version, nil when unrelated
+	Parent  *DocState          `json:"parent"`
+	Summary ComparisonSummary  `json:"summary"`
+	Details *ComparisonDetails `json:"details,omitempty"`
+}
+
+// ComparisonDetails holds the changes made to each side since the common parent
+type ComparisonDetails struct {
+	LeftChanges  ActionSummary `json:"leftChanges"`
+	RightChanges ActionSummary `json:"rightChanges"`
+}
+
+// ActionSummary lists the changes made to the tables of a document
+type ActionSummary struct {
+	// Pairs of old and new table IDs. The old ID is empty for added tables and the new ID for removed tables
+	TableRenames [][2]string            `json:"tableRenames"`
+	TableDeltas  map[TableID]TableDelta `json:"tableDeltas"`
+}
+
+// TableDelta lists the row and column changes of a table
+type TableDelta struct {
+	AddRows    []int `json:"addRows"`
+	UpdateRows []int `json:"updateRows"`
+	RemoveRows []int `json:"removeRows"`
+	// Pairs of old and new column IDs. The old ID is empty for added columns and the new ID for removed columns
+	ColumnRenames [][2]string `json:"columnRenames"`
+	// Changed cells of each column by row ID
+	ColumnDeltas map[string]map[int]CellDelta `json:"columnDeltas"`
+}
+
+// CellDelta holds the values of a cell before and after a change. A value is
+// nil when there is none, such as before a row was added, and flagged as
+// unknown when Grist doesn't know it.
+type CellDelta struct {
+	Before        json.RawMessage
+	After         json.RawMessage
+	BeforeUnknown bool
+	AfterUnknown  bool
+}
+
+func (d *CellDelta) UnmarshalJSON(b []byte) error {
+	// encoded as [[before] | "?" | null, [after] | "?" | null]
+	var delta [2]json.RawMessage
+	if err := json.Unmarshal(b, &delta); err != nil {
+		return err
+	}
+
+	*d = CellDelta{}
+
+	var err error
+	if d.Before, d.BeforeUnknown, err = decodeCellDeltaValue(delta[0]); err != nil {
+		return err
+	}
+
+	d.After, d.AfterUnknown, err = decodeCellDeltaValue(delta[1])
+
+	return err
+}
+
+// decodeCellDeltaValue decodes one side of a cell delta, returning whether the value is unknown
+func decodeCellDeltaValue(b json.RawMessage) (json.RawMessage, bool, error) {
+	if len(b) == 0 || string(b) == "null" {
+		return nil, false, nil
+	}
+
+	var unknown string
+	if err := json.Unmarshal(b, &unknown); err == nil {
+		if unknown != "?" {
+			return nil, false, fmt.Errorf("unexpected cell delta value %s", b)
+		}
+
+		return nil, true, nil
+	}
+
+	var value [1]json.RawMessage
+	if err := json.Unmarshal(b, &value); err != nil {
+		return nil, false, err
+	}
+
+	return value[0], false, nil
+}
+
+// GetDocStates gets the recent states of a document, most recent first
+func (c *Client) GetDocStates(document DocumentID) ([]DocState, error) {
+	return c.GetDocStatesContext(context.Background(), document)
+}
+
+// GetDocStatesContext gets the recent states of a document using the provided context
+func (c *Client) GetDocStatesContext(ctx context.Context, document DocumentID) ([]DocState, error) {
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/states", document),
+		Method: http.MethodGet,
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var s DocStates
+	if err := json.Unmarshal(resp, &s); err != nil {
+		return nil, err
+	}
+
+	return s.States, nil
+}
+
+// CompareDocuments compares a document with another document, such as a copy
+// or fork of it. If detail is set the changes on each side are included.
+func (c *Client) CompareDocuments(document, other DocumentID, detail bool) (DocStateComparison, error) {
+	return c.CompareDocumentsContext(context.Background(), document, other, detail)
+}
+
+// CompareDocumentsContext compares a document with another document using the provided context
+func (c *Client) CompareDocumentsContext(ctx context.Context, document, other DocumentID, detail bool) (DocStateComparison, error) {
+	var q url.Values
+	if detail {
+		q = url.Values{"detail": []string{"1"}}
+	}
+
+	return c.compare(ctx, fmt.Sprintf("/api/docs/%s/compare/%s", document, other), q)
+}
+
+// CompareVersions compares two versions of a document identified by their state hashes
+func (c *Client) CompareVersions(document DocumentID, left, right string) (DocStateComparison, error) {
+	return c.CompareVersionsContext(context.Background(), document, left, right)
+}
+
+// CompareVersionsContext compares two versions of a document using the provided context
+func (c *Client) CompareVersionsContext(ctx context.Context, document DocumentID, left, right string) (DocStateComparison, error) {
+	if left == "" || right == "" {
+		return DocStateComparison{}, errors.New("left and right state hashes required to compare versions")
+	}
+
+	q := url.Values{
+		"left":  []string{left},
+		"right": []string{right},
+	}
+
+	return c.compare(ctx, fmt.Sprintf("/api/docs/%s/compare", document), q)
+}
+
+func (c *Client) compare(ctx context.Context, path string, q url.Values) (DocStateComparison, error) {
+	var cmp DocStateComparison
+
+	request := GristRequest{
+		Path:   path,
+		Method: http.MethodGet,
+		Query:  q,
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return cmp, err
+	}
+
+	err = json.Unmarshal(resp, &cmp)
+
+	return cmp, err
+}
@@ -16,6 +16,7 @@ package gorist
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -41,42 +42,138 @@ type TableFields struct {
 
 // ListTables gets all tables for the specified document ID
 func (c *Client) ListTables(document DocumentID) (json.RawMessage, error) {
+	return c.ListTablesContext(context.Background(), document)
+}
+
+// ListTablesContext gets all tables for the specified document ID using the provided context
+func (c *Client) ListTablesContext(ctx context.Context, document DocumentID) (json.RawMessage, error) {
 	request := GristRequest{
 		Path:   fmt.Sprintf("/api/docs/%s/tables", document),
 		Method: http.MethodGet,
 	}
 
-	return c.httpRequest(request)
+	return c.httpRequest(ctx, request)
+}
+
+// ListTablesTyped gets all tables for the specified document ID. The columns of the tables aren't included
+func (c *Client) ListTablesTyped(document DocumentID) ([]Table, error) {
+	return c.ListTablesTypedContext(context.Background(), document)
+}
+
+// ListTablesTypedContext gets all tables for the specified document ID using the provided context
+func (c *Client) ListTablesTypedContext(ctx context.Context, document DocumentID) ([]Table, error) {
+	resp, err := c.ListTablesContext(ctx, document)
+	if err != nil {
+		return nil, err
+	}
+
+	var t Tables
+	if err := json.Unmarshal(resp, &t); err != nil {
+		return nil, err
+	}
+
+	return t.Tables, nil
 }
 
 // CreateTables creates tables in the specified document
 func (c *Client) CreateTables(document DocumentID, tables ...Table) (json.RawMessage, error) {
-	return c.createTables(document, tables...)
+	return c.createTables(context.Background(), document, tables...)
 }
 
-func (c *Client) createTables(document DocumentID, tables ...Table) (json.RawMessage, error) {
+// CreateTablesContext creates tables in the specified document using the provided context
+func (c *Client) CreateTablesContext(ctx context.Context, document DocumentID, tables ...Table) (json.RawMessage, error) {
+	return c.createTables(ctx, document, tables...)
+}
+
+func (c *Client) createTables(ctx context.Context, document DocumentID, tables ...Table) (json.RawMessage, error) {
 	for _, v := range tables {
 		if len(v.Columns) < 1 {
 			return nil, errors.New("columns required to create table")
 		}
 	}
 
-	return c.writeTable(http.MethodPost, document, tables...)
+	return c.writeTable(ctx, http.MethodPost, document, tables...)
 
 }
 
-// Patch for tables doesn't follow the spec and doesn't seem to work
-//func (c *Client) PatchTables(document Document, tables ...Table) (json.RawMessage, error) {
-//	for _, v := range tables {
-//		if v.Fields == (TableFields{}) {
-//			return nil, errors.New("fields required to patch table")
-//		}
-//	}
-//
-//	return c.writeTable(http.MethodPatch, document, tables...)
-//}
+// PatchTables updates the fields of existing tables. Only onDemand can be
+// changed; use RenameTable to change the ID of a table.
+func (c *Client) PatchTables(document DocumentID, tables ...Table) (json.RawMessage, error) {
+	return c.PatchTablesContext(context.Background(), document, tables...)
+}
+
+// PatchTablesContext updates the fields of existing tables using the provided context
+func (c *Client) PatchTablesContext(ctx context.Context, document DocumentID, tables ...Table) (json.RawMessage, error) {
+	// Grist rejects the columns and tableRef of a table when patching, so only
+	// send the fields it accepts
+	type patchFields struct {
+		OnDemand bool `json:"onDemand"`
+	}
+
+	type patch struct {
+		ID     TableID     `json:"id"`
+		Fields patchFields `json:"fields"`
+	}
+
+	patches := make([]patch, 0, len(tables))
+	for _, v := range tables {
+		if v.ID == "" {
+			return nil, errors.New("id required to patch table")
+		}
+
+		patches = append(patches, patch{
+			ID:     v.ID,
+			Fields: patchFields{OnDemand: v.Fields.OnDemand},
+		})
+	}
+
+	data, err := json.Marshal(map[string][]patch{"tables": patches})
+	if err != nil {
+		return nil, err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/tables", document),
+		Method: http.MethodPatch,
+		Data:   bytes.NewReader(data),
+	}
+
+	return c.httpRequest(ctx, request)
+}
+
+// RenameTable changes the ID of a table. The REST API can't rename tables so this applies the RenameTable user action
+func (c *Client) RenameTable(document DocumentID, table, newID TableID) error {
+	return c.RenameTableContext(context.Background(), document, table, newID)
+}
+
+// RenameTableContext changes the ID of a table using the provided context
+func (c *Client) RenameTableContext(ctx context.Context, document DocumentID, table, newID TableID) error {
+	if table == "" || newID == "" {
+		return errors.New("table ID and new ID required to rename table")
+	}
+
+	_, err := c.ApplyUserActionsContext(ctx, document, RenameTableAction(table, newID))
+
+	return err
+}
+
+// DeleteTable removes a table and its data. The REST API can't delete tables so this applies the RemoveTable user action
+func (c *Client) DeleteTable(document DocumentID, table TableID) error {
+	return c.DeleteTableContext(context.Background(), document, table)
+}
+
+// DeleteTableContext removes a table and its data using the provided context
+func (c *Client) DeleteTableContext(ctx context.Context, document DocumentID, table TableID) error {
+	if table == "" {
+		return errors.New("table ID required to delete table")
+	}
+
+	_, err := c.ApplyUserActionsContext(ctx, document, RemoveTableAction(table))
+
+	return err
+}
 
-func (c *Client) writeTable(method string, document DocumentID, tables ...Table) (json.RawMessage, error) {
+func (c *Client) writeTable(ctx context.Context, method string, document DocumentID, tables ...Table) (json.RawMessage, error) {
 	t := Tables{
 		Tables: tables,
 	}
@@ -91,6 +188,6 @@ func (c *Client) writeTable(method string, document DocumentID, tables ...Table)
 		Data:   bytes.NewReader(data),
 	}
 
-	return c.httpRequest(request)
+	return c.httpRequest(ctx, request)
 
 }
@@ -0,0 +1,218 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type WebhookID string
+
+type WebhookEventType string
+
+const (
+	WebhookAdd    WebhookEventType = "add"
+	WebhookUpdate WebhookEventType = "update"
+)
+
+type Webhooks struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+type Webhook struct {
+	ID     WebhookID     `json:"id,omitempty"`
+	Fields WebhookFields `json:"fields"`
+	// Only returned when listing webhooks
+	Usage *WebhookUsage `json:"usage,omitempty"`
+}
+
+// WebhookFields configures a webhook. Empty fields are left unchanged when updating.
+type WebhookFields struct {
+	Name       string             `json:"name,omitempty"`
+	Memo       string             `json:"memo,omitempty"`
+	URL        string             `json:"url,omitempty"`
+	EventTypes []WebhookEventType `json:"eventTypes,omitempty"`
+	TableID    TableID            `json:"tableId,omitempty"`
+	// Bool column that must be true before a record is sent
+	IsReadyColumn string `json:"isReadyColumn,omitempty"`
+	// Defaults to true when creating a webhook
+	Enabled *bool `json:"enabled,omitempty"`
+	// Only returned when listing webhooks
+	UnsubscribeKey string `json:"unsubscribeKey,omitempty"`
+}
+
+// WebhookUsage describes the state of the delivery queue of a webhook
+type WebhookUsage struct {
+	NumWaiting       int                 `json:"numWaiting"`
+	Status           string              `json:"status"`
+	UpdatedTime      int64               `json:"updatedTime,omitempty"`
+	LastSuccessTime  int64               `json:"lastSuccessTime,omitempty"`
+	LastFailureTime  int64               `json:"lastFailureTime,omitempty"`
+	LastErrorMessage string              `json:"lastErrorMessage,omitempty"`
+	LastHTTPStatus   int                 `json:"lastHttpStatus,omitempty"`
+	LastEventBatch   *WebhookBatchStatus `json:"lastEventBatch,omitempty"`
+}
+
+type WebhookBatchStatus struct {
+	Size         int    `json:"size"`
+	Attempts     int    `json:"attempts"`
+	Status       string `json:"status"`
+	HTTPStatus   int    `json:"httpStatus,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// ListWebhooks gets all webhooks of a document along with their queue usage
+func (c *Client) ListWebhooks(document DocumentID) ([]Webhook, error) {
+	return c.ListWebhooksContext(context.Background(), document)
+}
+
+// ListWebhooksContext gets all webhooks of a document using the provided context
+func (c *Client) ListWebhooksContext(ctx context.Context, document DocumentID) ([]Webhook, error) {
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/webhooks", document),
+		Method: http.MethodGet,
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var w Webhooks
+	if err := json.Unmarshal(resp, &w); err != nil {
+		return nil, err
+	}
+
+	return w.Webhooks, nil
+}
+
+// CreateWebhooks adds webhooks to a document and returns their IDs
+func (c *Client) CreateWebhooks(document DocumentID, webhooks ...WebhookFields) ([]WebhookID, error) {
+	return c.CreateWebhooksContext(context.Background(), document, webhooks...)
+}
+
+// CreateWebhooksContext adds webhooks to a document using the provided context
+func (c *Client) CreateWebhooksContext(ctx context.Context, document DocumentID, webhooks ...WebhookFields) ([]WebhookID, error) {
+	w := Webhooks{}
+	for _, v := range webhooks {
+		if v.URL == "" || v.TableID == "" || len(v.EventTypes) < 1 {
+			return nil, errors.New("url, table ID and event types required to create webhook")
+		}
+
+		w.Webhooks = append(w.Webhooks, Webhook{Fields: v})
+	}
+
+	data, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/webhooks", document),
+		Method: http.MethodPost,
+		Data:   bytes.NewReader(data),
+	}
+
+	resp, err := c.httpRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var created Webhooks
+	if err := json.Unmarshal(resp, &created); err != nil {
+		return nil, err
+	}
+
+	ids := make([]WebhookID, 0, len(created.Webhooks))
+	for _, v := range created.Webhooks {
+		ids = append(ids, v.ID)
+	}
+
+	return ids, nil
+}
+
+// UpdateWebhook modifies the non empty fields of a webhook
+func (c *Client) UpdateWebhook(document DocumentID, id WebhookID, fields WebhookFields) error {
+	return c.UpdateWebhookContext(context.Background(), document, id, fields)
+}
+
+// UpdateWebhookContext modifies the non empty fields of a webhook using the provided context
+func (c *Client) UpdateWebhookContext(ctx context.Context, document DocumentID, id WebhookID, fields WebhookFields) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/webhooks/%s", document, id),
+		Method: http.MethodPatch,
+		Data:   bytes.NewReader(data),
+	}
+
+	_, err = c.httpRequest(ctx, request)
+
+	return err
+}
+
+// DeleteWebhook removes a webhook from a document
+func (c *Client) DeleteWebhook(document DocumentID, id WebhookID) error {
+	return c.DeleteWebhookContext(context.Background(), document, id)
+}
+
+// DeleteWebhookContext removes a webhook from a document using the provided context
+func (c *Client) DeleteWebhookContext(ctx context.Context, document DocumentID, id WebhookID) error {
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/docs/%s/webhooks/%s", document, id),
+		Method: http.MethodDelete,
+	}
+
+	_, err := c.httpRequest(ctx, request)
+
+	return err
+}
+
+// ClearWebhookQueue drops the pending events of the given webhooks, or of every webhook in the document when no IDs are given
+func (c *Client) ClearWebhookQueue(document DocumentID, ids ...WebhookID) error {
+	return c.ClearWebhookQueueContext(context.Background(), document, ids...)
+}
+
+// ClearWebhookQueueContext drops pending webhook events using the provided context
+func (c *Client) ClearWebhookQueueContext(ctx context.Context, document DocumentID, ids ...WebhookID) error {
+	paths := []string{fmt.Sprintf("/api/docs/%s/webhooks/queue", document)}
+	if len(ids) > 0 {
+		paths = paths[:0]
+		for _, v := range ids {
+			paths = append(paths, fmt.Sprintf("/api/docs/%s/webhooks/queue/%s", document, v))
+		}
+	}
+
+	for _, v := range paths {
+		request := GristRequest{
+			Path:   v,
+			Method: http.MethodDelete,
+		}
+
+		if _, err := c.httpRequest(ctx, request); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
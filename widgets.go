@@ -0,0 +1,153 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorist
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// WidgetOptions is the structured form of the widgetOptions JSON of a column.
+// Options without a field are kept in Extra so they survive a round trip.
+type WidgetOptions struct {
+	Widget    string `json:"widget,omitempty"`
+	Alignment string `json:"alignment,omitempty"`
+	Wrap      bool   `json:"wrap,omitempty"`
+
+	// Choice and ChoiceList columns
+	Choices       []string                `json:"choices,omitempty"`
+	ChoiceOptions map[string]ChoiceOption `json:"choiceOptions,omitempty"`
+
+	// Date and DateTime columns
+	DateFormat         string `json:"dateFormat,omitempty"`
+	IsCustomDateFormat bool   `json:"isCustomDateFormat,omitempty"`
+	TimeFormat         string `json:"timeFormat,omitempty"`
+	IsCustomTimeFormat bool   `json:"isCustomTimeFormat,omitempty"`
+
+	// Numeric and Int columns
+	NumMode     string `json:"numMode,omitempty"`
+	NumSign     string `json:"numSign,omitempty"`
+	Decimals    *int   `json:"decimals,omitempty"`
+	MaxDecimals *int   `json:"maxDecimals,omitempty"`
+	Currency    string `json:"currency,omitempty"`
+
+	// Cell style
+	TextColor         string `json:"textColor,omitempty"`
+	FillColor         string `json:"fillColor,omitempty"`
+	FontBold          bool   `json:"fontBold,omitempty"`
+	FontItalic        bool   `json:"fontItalic,omitempty"`
+	FontUnderline     bool   `json:"fontUnderline,omitempty"`
+	FontStrikethrough bool   `json:"fontStrikethrough,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// ChoiceOption is the style of a single choice
+type ChoiceOption struct {
+	TextColor         string `json:"textColor,omitempty"`
+	FillColor         string `json:"fillColor,omitempty"`
+	FontBold          bool   `json:"fontBold,omitempty"`
+	FontItalic        bool   `json:"fontItalic,omitempty"`
+	FontUnderline     bool   `json:"fontUnderline,omitempty"`
+	FontStrikethrough bool   `json:"fontStrikethrough,omitempty"`
+}
+
+type widgetOptionsAlias WidgetOptions
+
+func (w WidgetOptions) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(widgetOptionsAlias(w))
+	if err != nil || len(w.Extra) == 0 {
+		return data, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	for k, v := range w.Extra {
+		if _, ok := all[k]; !ok {
+			all[k] = v
+		}
+	}
+
+	return json.Marshal(all)
+}
+
+func (w *WidgetOptions) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, (*widgetOptionsAlias)(w)); err != nil {
+		return err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(b, &all); err != nil {
+		return err
+	}
+
+	w.Extra = nil
+	for k, v := range all {
+		if widgetOptionNames[k] {
+			continue
+		}
+
+		if w.Extra == nil {
+			w.Extra = map[string]json.RawMessage{}
+		}
+		w.Extra[k] = v
+	}
+
+	return nil
+}
+
+// widgetOptionNames holds the JSON names of the WidgetOptions fields
+var widgetOptionNames = func() map[string]bool {
+	names := map[string]bool{}
+
+	t := reflect.TypeOf(WidgetOptions{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+
+	return names
+}()
+
+// ParseWidgetOptions decodes the widgetOptions JSON of the column
+func (f ColumnField) ParseWidgetOptions() (WidgetOptions, error) {
+	var w WidgetOptions
+
+	if f.WidgetOptions == "" {
+		return w, nil
+	}
+
+	err := json.Unmarshal([]byte(f.WidgetOptions), &w)
+
+	return w, err
+}
+
+// SetWidgetOptions encodes w as the widgetOptions JSON of the column
+func (f *ColumnField) SetWidgetOptions(w WidgetOptions) error {
+	data, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+
+	f.WidgetOptions = string(data)
+
+	return nil
+}
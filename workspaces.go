@@ -16,9 +16,11 @@ package gorist
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 )
@@ -43,6 +45,11 @@ type Workspace struct {
 
 // NewWorkspace creates a new worksapce in the specified organization
 func (c *Client) CreateWorkspace(orgID int, name string) (int, error) {
+	return c.CreateWorkspaceContext(context.Background(), orgID, name)
+}
+
+// CreateWorkspaceContext creates a new workspace in the specified organization using the provided context
+func (c *Client) CreateWorkspaceContext(ctx context.Context, orgID int, name string) (int, error) {
 	wn := WorkspaceName{
 		Name: name,
 	}
@@ -58,7 +65,7 @@ func (c *Client) CreateWorkspace(orgID int, name string) (int, error) {
 		Data:   bytes.NewReader(data),
 	}
 
-	id, err := c.httpRequest(request)
+	id, err := c.httpRequest(ctx, request)
 	if err != nil {
 		return 0, err
 	}
@@ -67,10 +74,117 @@ func (c *Client) CreateWorkspace(orgID int, name string) (int, error) {
 }
 
 func (c *Client) GetWorkspace(id WorkspaceID) (json.RawMessage, error) {
+	return c.GetWorkspaceContext(context.Background(), id)
+}
+
+// GetWorkspaceContext gets the details of a workspace using the provided context
+func (c *Client) GetWorkspaceContext(ctx context.Context, id WorkspaceID) (json.RawMessage, error) {
 	request := GristRequest{
 		Path:   fmt.Sprintf("/api/workspaces/%d", id),
 		Method: http.MethodGet,
 	}
 
-	return c.httpRequest(request)
+	return c.httpRequest(ctx, request)
+}
+
+// GetWorkspaceTyped gets the details of a workspace
+func (c *Client) GetWorkspaceTyped(id WorkspaceID) (Workspace, error) {
+	return c.GetWorkspaceTypedContext(context.Background(), id)
+}
+
+// GetWorkspaceTypedContext gets the details of a workspace using the provided context
+func (c *Client) GetWorkspaceTypedContext(ctx context.Context, id WorkspaceID) (Workspace, error) {
+	var w Workspace
+
+	resp, err := c.GetWorkspaceContext(ctx, id)
+	if err != nil {
+		return w, err
+	}
+
+	err = json.Unmarshal(resp, &w)
+
+	return w, err
+}
+
+// UpdateWorkspace renames a workspace and returns the updated workspace
+func (c *Client) UpdateWorkspace(id WorkspaceID, name string) (Workspace, error) {
+	return c.UpdateWorkspaceContext(context.Background(), id, name)
+}
+
+// UpdateWorkspaceContext renames a workspace using the provided context
+func (c *Client) UpdateWorkspaceContext(ctx context.Context, id WorkspaceID, name string) (Workspace, error) {
+	data, err := json.Marshal(WorkspaceName{Name: name})
+	if err != nil {
+		return Workspace{}, err
+	}
+
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/workspaces/%d", id),
+		Method: http.MethodPatch,
+		Data:   bytes.NewReader(data),
+	}
+
+	if _, err := c.httpRequest(ctx, request); err != nil {
+		return Workspace{}, err
+	}
+
+	return c.GetWorkspaceTypedContext(ctx, id)
+}
+
+// DeleteWorkspace permanently deletes a workspace and its documents
+func (c *Client) DeleteWorkspace(id WorkspaceID) error {
+	return c.DeleteWorkspaceContext(context.Background(), id)
+}
+
+// DeleteWorkspaceContext permanently deletes a workspace using the provided context
+func (c *Client) DeleteWorkspaceContext(ctx context.Context, id WorkspaceID) error {
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/workspaces/%d", id),
+		Method: http.MethodDelete,
+	}
+
+	_, err := c.httpRequest(ctx, request)
+
+	return err
+}
+
+// RemoveWorkspace moves a workspace to the trash where it can be restored with
+// RestoreWorkspace. If permanent is set the workspace is deleted instead.
+func (c *Client) RemoveWorkspace(id WorkspaceID, permanent bool) error {
+	return c.RemoveWorkspaceContext(context.Background(), id, permanent)
+}
+
+// RemoveWorkspaceContext moves a workspace to the trash using the provided context
+func (c *Client) RemoveWorkspaceContext(ctx context.Context, id WorkspaceID, permanent bool) error {
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/workspaces/%d/remove", id),
+		Method: http.MethodPost,
+	}
+
+	if permanent {
+		request.Query = url.Values{"permanent": []string{"1"}}
+	}
+
+	_, err := c.httpRequest(ctx, request)
+
+	return err
+}
+
+// RestoreWorkspace restores a workspace from the trash and returns it
+func (c *Client) RestoreWorkspace(id WorkspaceID) (Workspace, error) {
+	return c.RestoreWorkspaceContext(context.Background(), id)
+}
+
+// RestoreWorkspaceContext restores a workspace from the trash using the provided context
+func (c *Client) RestoreWorkspaceContext(ctx context.Context, id WorkspaceID) (Workspace, error) {
+	request := GristRequest{
+		Path:   fmt.Sprintf("/api/workspaces/%d/unremove", id),
+		Method: http.MethodPost,
+	}
+
+	if _, err := c.httpRequest(ctx, request); err != nil {
+		return Workspace{}, err
+	}
+
+	return c.GetWorkspaceTypedContext(ctx, id)
 }